@@ -0,0 +1,237 @@
+package preset
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AccessMatrix is who has what access across a team: a row per user and a column per workspace
+type AccessMatrix struct {
+	TeamID     int               `json:"team_id"`
+	Workspaces []AccessWorkspace `json:"workspaces"`
+	Users      []AccessRow       `json:"users"`
+}
+
+// AccessWorkspace is a column of an AccessMatrix
+type AccessWorkspace struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+// AccessRow is a user's access across a team
+type AccessRow struct {
+	UserID    int    `json:"user_id"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	Onboarded bool   `json:"onboarded"`
+	// Team role name, empty if the user only shows up as a workspace member
+	TeamRole          string `json:"team_role"`
+	TeamRoleFromGroup bool   `json:"team_role_from_group"`
+	// Roles by workspace ID; workspaces the user has no role in are left out
+	Workspaces map[int]AccessCell `json:"workspaces"`
+}
+
+// AccessCell is a user's role in a workspace
+type AccessCell struct {
+	Role           string `json:"role"`
+	RoleIdentifier string `json:"role_identifier"`
+	FromGroup      bool   `json:"from_group"`
+}
+
+// Returns a role name, marked when it comes from a group
+func (c AccessCell) String() string {
+	if c.FromGroup {
+		return c.Role + " (group)"
+	}
+	return c.Role
+}
+
+// Builds the access matrix of a team, fetching its members, workspaces and each workspace's
+// members concurrently. Users are sorted by email and workspaces by ID. Cancelling ctx aborts the
+// requests in flight and stops further ones.
+func (c *PresetClient) BuildAccessMatrix(ctx context.Context, teamID int, authToken *string) (*AccessMatrix, error) {
+	var (
+		wg            sync.WaitGroup
+		teamMembers   *[]TeamMembership
+		workspaces    *[]Workspace
+		membersErr    error
+		workspacesErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		teamMembers, membersErr = c.getTeamMembership(ctx, teamID, 0, authToken)
+	}()
+	go func() {
+		defer wg.Done()
+		workspaces, workspacesErr = c.getAllWorkspaces(ctx, teamID, authToken)
+	}()
+	wg.Wait()
+
+	if membersErr != nil {
+		return nil, fmt.Errorf("getting team members: %w", membersErr)
+	}
+	if workspacesErr != nil {
+		return nil, fmt.Errorf("getting workspaces: %w", workspacesErr)
+	}
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]Workspace{}, *workspaces...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	results, err := runBulk(ctx, sorted, BulkOptions{StopOnError: true}, func(ctx context.Context, workspace Workspace) (*[]WorkspaceMembership, error) {
+		return c.getWorkspaceMembership(ctx, teamID, workspace.ID, authToken)
+	})
+	if err != nil {
+		for _, result := range results {
+			if result.Err != nil && !errors.Is(result.Err, ErrSkipped) {
+				return nil, fmt.Errorf("getting members of workspace %d: %w", result.Change.ID, result.Err)
+			}
+		}
+		return nil, err
+	}
+
+	matrix := &AccessMatrix{TeamID: teamID, Workspaces: []AccessWorkspace{}, Users: []AccessRow{}}
+	rows := map[int]*AccessRow{}
+	row := func(user User) *AccessRow {
+		if r, ok := rows[user.ID]; ok {
+			return r
+		}
+		r := &AccessRow{
+			UserID:     user.ID,
+			Email:      user.Email,
+			Name:       strings.TrimSpace(user.FirstName + " " + user.LastName),
+			Onboarded:  user.Onboarded,
+			Workspaces: map[int]AccessCell{},
+		}
+		rows[user.ID] = r
+		return r
+	}
+
+	for _, membership := range *teamMembers {
+		r := row(membership.User)
+		r.TeamRole = membership.TeamRole.Name
+		r.TeamRoleFromGroup = membership.IsRoleFromGroup
+	}
+
+	for _, result := range results {
+		workspace := result.Change
+		matrix.Workspaces = append(matrix.Workspaces, AccessWorkspace{ID: workspace.ID, Name: workspace.Name, Title: workspace.Title})
+		for _, membership := range *result.Result {
+			row(membership.User).Workspaces[workspace.ID] = AccessCell{
+				Role:           membership.WorkspaceRole.Name,
+				RoleIdentifier: membership.WorkspaceRole.RoleIdentifier,
+				FromGroup:      membership.IsRoleFromGroup,
+			}
+		}
+	}
+
+	for _, r := range rows {
+		matrix.Users = append(matrix.Users, *r)
+	}
+	sort.Slice(matrix.Users, func(i, j int) bool {
+		if matrix.Users[i].Email != matrix.Users[j].Email {
+			return matrix.Users[i].Email < matrix.Users[j].Email
+		}
+		return matrix.Users[i].UserID < matrix.Users[j].UserID
+	})
+
+	return matrix, nil
+}
+
+// Returns the header and rows of the matrix as text, with a column per workspace titled by
+// the workspace title
+func (m *AccessMatrix) table() ([]string, [][]string) {
+	header := []string{"User ID", "Email", "Name", "Onboarded", "Team role"}
+	for _, workspace := range m.Workspaces {
+		title := workspace.Title
+		if title == "" {
+			title = workspace.Name
+		}
+		header = append(header, title)
+	}
+
+	rows := [][]string{}
+	for _, user := range m.Users {
+		teamRole := user.TeamRole
+		if user.TeamRoleFromGroup {
+			teamRole += " (group)"
+		}
+		row := []string{strconv.Itoa(user.UserID), user.Email, user.Name, strconv.FormatBool(user.Onboarded), teamRole}
+		for _, workspace := range m.Workspaces {
+			row = append(row, user.Workspaces[workspace.ID].String())
+		}
+		rows = append(rows, row)
+	}
+
+	return header, rows
+}
+
+// Writes the matrix as CSV, with a column per workspace. Group-derived roles are suffixed with
+// " (group)".
+func (m *AccessMatrix) WriteCSV(w io.Writer) error {
+	header, rows := m.table()
+
+	writer := csv.NewWriter(w)
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// Writes the matrix as indented JSON
+func (m *AccessMatrix) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m)
+}
+
+// Characters that would break a cell of a Markdown table
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+// Writes the matrix as a Markdown table, with a column per workspace
+func (m *AccessMatrix) WriteMarkdown(w io.Writer) error {
+	header, rows := m.table()
+
+	escape := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = markdownCellEscaper.Replace(cell)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |\n"
+	}
+
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	_, err := io.WriteString(w, escape(header)+escape(separator))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		_, err = io.WriteString(w, escape(row))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
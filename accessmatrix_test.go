@@ -0,0 +1,177 @@
+package preset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a mock server for a team with two workspaces
+func accessMatrixServer(t *testing.T) *httptest.Server {
+	responses := map[string]string{
+		"/v1/team/1/memberships": `{"payload": [
+			{"team_role": {"id": 1, "name": "Admin"}, "user": {"id": 10, "email": "bob@example.com", "first_name": "Bob", "onboarded": true}},
+			{"is_role_from_group": true, "team_role": {"id": 2, "name": "User"}, "user": {"id": 11, "email": "ada@example.com", "first_name": "Ada", "last_name": "L"}}
+		]}`,
+		"/v1/teams/1/workspaces": `{"payload": [
+			{"id": 6, "name": "ws6", "title": "Sales | EMEA"},
+			{"id": 5, "name": "ws5", "title": "Finance"}
+		]}`,
+		"/v1/teams/1/workspaces/5/memberships": `{"payload": [
+			{"user": {"id": 10, "email": "bob@example.com"}, "workspace_role": {"name": "Workspace Admin", "role_identifier": "Admin"}}
+		]}`,
+		"/v1/teams/1/workspaces/6/memberships": `{"payload": [
+			{"is_role_from_group": true, "user": {"id": 11, "email": "ada@example.com"}, "workspace_role": {"name": "Viewer", "role_identifier": "PresetReportsOnly"}},
+			{"user": {"id": 12, "email": "carl@example.com"}, "workspace_role": {"name": "Viewer", "role_identifier": "PresetReportsOnly"}}
+		]}`,
+	}
+
+	// Create a mock HTTP server
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+}
+
+func TestBuildAccessMatrix(t *testing.T) {
+	mockServer := accessMatrixServer(t)
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	matrix, err := client.BuildAccessMatrix(context.Background(), 1, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []AccessWorkspace{{ID: 5, Name: "ws5", Title: "Finance"}, {ID: 6, Name: "ws6", Title: "Sales | EMEA"}}, matrix.Workspaces)
+	assert.Len(t, matrix.Users, 3)
+
+	ada := matrix.Users[0]
+	assert.Equal(t, "ada@example.com", ada.Email)
+	assert.Equal(t, "Ada L", ada.Name)
+	assert.Equal(t, "User", ada.TeamRole)
+	assert.True(t, ada.TeamRoleFromGroup)
+	assert.Equal(t, AccessCell{Role: "Viewer", RoleIdentifier: "PresetReportsOnly", FromGroup: true}, ada.Workspaces[6])
+	assert.NotContains(t, ada.Workspaces, 5)
+
+	bob := matrix.Users[1]
+	assert.True(t, bob.Onboarded)
+	assert.Equal(t, "Workspace Admin", bob.Workspaces[5].Role)
+
+	// Workspace members missing from the team still show up, without a team role
+	carl := matrix.Users[2]
+	assert.Equal(t, "", carl.TeamRole)
+	assert.Equal(t, "Viewer", carl.Workspaces[6].Role)
+}
+
+func TestBuildAccessMatrix_Error(t *testing.T) {
+	mockServer := accessMatrixServer(t)
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	_, err := client.BuildAccessMatrix(context.Background(), 2, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status: 404")
+}
+
+func TestAccessMatrix_Exporters(t *testing.T) {
+	mockServer := accessMatrixServer(t)
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	matrix, err := client.BuildAccessMatrix(context.Background(), 1, nil)
+	assert.NoError(t, err)
+
+	var csvOut bytes.Buffer
+	assert.NoError(t, matrix.WriteCSV(&csvOut))
+	assert.Equal(t, strings.Join([]string{
+		"User ID,Email,Name,Onboarded,Team role,Finance,Sales | EMEA",
+		"11,ada@example.com,Ada L,false,User (group),,Viewer (group)",
+		"10,bob@example.com,Bob,true,Admin,Workspace Admin,",
+		"12,carl@example.com,,false,,,Viewer",
+	}, "\n")+"\n", csvOut.String())
+
+	var markdown bytes.Buffer
+	assert.NoError(t, matrix.WriteMarkdown(&markdown))
+	lines := strings.Split(markdown.String(), "\n")
+	assert.Equal(t, `| User ID | Email | Name | Onboarded | Team role | Finance | Sales \| EMEA |`, lines[0])
+	assert.Equal(t, "| --- | --- | --- | --- | --- | --- | --- |", lines[1])
+	assert.Equal(t, "| 10 | bob@example.com | Bob | true | Admin | Workspace Admin |  |", lines[3])
+
+	var jsonOut bytes.Buffer
+	assert.NoError(t, matrix.WriteJSON(&jsonOut))
+	decoded := AccessMatrix{}
+	assert.NoError(t, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	assert.Equal(t, *matrix, decoded)
+}
+
+func TestBuildAccessMatrix_CancelDuringTeamFetch(t *testing.T) {
+	started := make(chan struct{}, 1)
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/team/1/memberships" {
+			started <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	begun := time.Now()
+	_, err := client.BuildAccessMatrix(ctx, 1, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(begun), 5*time.Second)
+}
+
+func TestAccessMatrix_MarkdownEscapesNewlines(t *testing.T) {
+	matrix := &AccessMatrix{
+		TeamID:     1,
+		Workspaces: []AccessWorkspace{{ID: 5, Title: "Fin\nance"}},
+		Users: []AccessRow{
+			{UserID: 10, Email: "bob@example.com", Name: "Bob\r\nSmith", Workspaces: map[int]AccessCell{5: {Role: "Viewer"}}},
+		},
+	}
+
+	var markdown bytes.Buffer
+	assert.NoError(t, matrix.WriteMarkdown(&markdown))
+	lines := strings.Split(strings.TrimSuffix(markdown.String(), "\n"), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "| User ID | Email | Name | Onboarded | Team role | Fin ance |", lines[0])
+	assert.Equal(t, "| 10 | bob@example.com | Bob Smith | false |  | Viewer |", lines[2])
+}
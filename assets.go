@@ -0,0 +1,379 @@
+package preset
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AssetKind identifies a Superset resource type that can be exported and imported
+type AssetKind string
+
+const (
+	ASSET_DATABASE    AssetKind = "database"
+	ASSET_DATASET     AssetKind = "dataset"
+	ASSET_CHART       AssetKind = "chart"
+	ASSET_DASHBOARD   AssetKind = "dashboard"
+	ASSET_SAVED_QUERY AssetKind = "saved_query"
+	// Every asset of the workspace, as exported by the assets endpoint
+	ASSET_ALL AssetKind = "assets"
+)
+
+// Directory that holds the YAML files of each asset kind inside a bundle
+var assetDirectories = map[AssetKind]string{
+	ASSET_DATABASE:    "databases",
+	ASSET_DATASET:     "datasets",
+	ASSET_CHART:       "charts",
+	ASSET_DASHBOARD:   "dashboards",
+	ASSET_SAVED_QUERY: "queries",
+}
+
+// Returns the directory that holds the YAML files of the kind inside a bundle, or "" for ASSET_ALL
+func (k AssetKind) Directory() string {
+	return assetDirectories[k]
+}
+
+// Asset kind matching the "type" recorded in a bundle's metadata.yaml
+var assetMetadataTypes = map[string]AssetKind{
+	"Database":   ASSET_DATABASE,
+	"SqlaTable":  ASSET_DATASET,
+	"Slice":      ASSET_CHART,
+	"Dashboard":  ASSET_DASHBOARD,
+	"SavedQuery": ASSET_SAVED_QUERY,
+	"assets":     ASSET_ALL,
+}
+
+// Exports the given assets, along with everything they depend on, as an in-memory bundle
+func (s *SupersetClient) ExportAssets(ctx context.Context, kind AssetKind, ids []int) (*AssetBundle, error) {
+	if _, found := assetDirectories[kind]; !found {
+		return nil, fmt.Errorf("invalid asset kind")
+	}
+
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = strconv.Itoa(id)
+	}
+
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("!(%s)", strings.Join(idStrings, ",")))
+
+	req, err := s.newRequest(ctx, "ExportAssets", "GET", fmt.Sprintf("/api/v1/%s/export/?%s", kind, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadAssetBundle(body)
+}
+
+// Exports every database, dataset, chart, dashboard and saved query of the workspace as one bundle
+func (s *SupersetClient) ExportAllAssets(ctx context.Context) (*AssetBundle, error) {
+	req, err := s.newRequest(ctx, "ExportAllAssets", "GET", "/api/v1/assets/export/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadAssetBundle(body)
+}
+
+// Uploads a bundle to the workspace. passwords maps database file paths within the bundle,
+// e.g. databases/warehouse.yaml, to the password of that database.
+func (s *SupersetClient) ImportAssets(ctx context.Context, bundle *AssetBundle, overwrite bool, passwords map[string]string) error {
+	kind, err := bundle.Kind()
+	if err != nil {
+		return err
+	}
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+
+	// The assets endpoint always overwrites and expects the archive under a different field name
+	fieldName := "formData"
+	if kind == ASSET_ALL {
+		fieldName = "bundle"
+	}
+
+	file, err := writer.CreateFormFile(fieldName, fmt.Sprintf("%s.zip", bundle.Root))
+	if err != nil {
+		return err
+	}
+
+	err = bundle.WriteZip(file)
+	if err != nil {
+		return err
+	}
+
+	err = writer.WriteField("overwrite", strconv.FormatBool(overwrite))
+	if err != nil {
+		return err
+	}
+
+	if len(passwords) > 0 {
+		passwordBytes, err := s.Preset.codec().Marshal(passwords)
+		if err != nil {
+			return err
+		}
+
+		err = writer.WriteField("passwords", string(passwordBytes))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, "ImportAssets", "POST", fmt.Sprintf("/api/v1/%s/import/", kind), nil)
+	if err != nil {
+		return err
+	}
+	req.Body = io.NopCloser(&form)
+	req.ContentLength = int64(form.Len())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Parses a Superset export ZIP into a bundle
+func ReadAssetBundle(data []byte) (*AssetBundle, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := AssetBundle{Files: map[string][]byte{}}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if !isLocalPath(f.Name) {
+			return nil, fmt.Errorf("unsafe file name %s in bundle", f.Name)
+		}
+
+		root, name, found := strings.Cut(f.Name, "/")
+		if !found {
+			return nil, fmt.Errorf("unexpected file %s outside of the bundle directory", f.Name)
+		}
+		if bundle.Root != "" && bundle.Root != root {
+			return nil, fmt.Errorf("bundle has more than one top-level directory")
+		}
+		bundle.Root = root
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Files[name] = content
+	}
+
+	return &bundle, nil
+}
+
+// Writes the bundle as a ZIP archive in the layout Superset expects on import
+func (b *AssetBundle) WriteZip(w io.Writer) error {
+	writer := zip.NewWriter(w)
+
+	for _, name := range b.paths() {
+		file, err := writer.Create(path.Join(b.Root, name))
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write(b.Files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// Returns the asset kind the bundle was exported as, read from its metadata.yaml
+func (b *AssetBundle) Kind() (AssetKind, error) {
+	content, found := b.Files["metadata.yaml"]
+	if !found {
+		return "", fmt.Errorf("bundle has no metadata.yaml")
+	}
+
+	metadata := struct {
+		Type string `yaml:"type"`
+	}{}
+	err := yaml.Unmarshal(content, &metadata)
+	if err != nil {
+		return "", err
+	}
+
+	kind, found := assetMetadataTypes[metadata.Type]
+	if !found {
+		return "", fmt.Errorf("unsupported bundle type %q", metadata.Type)
+	}
+
+	return kind, nil
+}
+
+// Returns the paths of the YAML files holding assets of the given kind, in sorted order
+func (b *AssetBundle) Paths(kind AssetKind) []string {
+	prefix := assetDirectories[kind] + "/"
+
+	paths := []string{}
+	for _, name := range b.paths() {
+		if strings.HasPrefix(name, prefix) {
+			paths = append(paths, name)
+		}
+	}
+
+	return paths
+}
+
+// Replaces the SQLAlchemy URI of every database in the bundle whose database_name is a key of uris
+func (b *AssetBundle) RewriteDatabaseURIs(uris map[string]string) error {
+	for _, name := range b.Paths(ASSET_DATABASE) {
+		doc := yaml.Node{}
+		err := yaml.Unmarshal(b.Files[name], &doc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		databaseName := yamlMappingValue(&doc, "database_name")
+		uriNode := yamlMappingValue(&doc, "sqlalchemy_uri")
+		if databaseName == nil || uriNode == nil {
+			continue
+		}
+
+		uri, found := uris[databaseName.Value]
+		if !found {
+			continue
+		}
+		uriNode.Value = uri
+
+		content, err := marshalYAML(&doc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		b.Files[name] = content
+	}
+
+	return nil
+}
+
+// Replaces every occurrence of the keys of uuids with their values across all files of the bundle.
+//
+// All keys are replaced in a single pass, so chained or swapped mappings (a to b and b to a) don't
+// rewrite a UUID twice.
+func (b *AssetBundle) RewriteUUIDs(uuids map[string]string) {
+	// Longer keys first so that a key which is a prefix of another one never shadows it
+	from := make([]string, 0, len(uuids))
+	for uuid := range uuids {
+		from = append(from, uuid)
+	}
+	sort.Slice(from, func(i, j int) bool {
+		if len(from[i]) != len(from[j]) {
+			return len(from[i]) > len(from[j])
+		}
+		return from[i] < from[j]
+	})
+
+	oldnew := make([]string, 0, 2*len(from))
+	for _, uuid := range from {
+		oldnew = append(oldnew, uuid, uuids[uuid])
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	for name, content := range b.Files {
+		b.Files[name] = []byte(replacer.Replace(string(content)))
+	}
+}
+
+// Reports whether a slash-separated bundle path stays inside the directory it is joined to,
+// i.e. is relative and has no .. element, even one that cleans away. Backslashes are refused as well, since they are
+// separators on Windows.
+func isLocalPath(name string) bool {
+	if strings.Contains(name, `\`) || !filepath.IsLocal(filepath.FromSlash(name)) {
+		return false
+	}
+
+	for _, element := range strings.Split(name, "/") {
+		if element == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *AssetBundle) paths() []string {
+	paths := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// Encodes value as YAML using the two-space indentation of Superset exports
+func marshalYAML(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Returns the value node stored under key in a YAML document whose root is a mapping
+func yamlMappingValue(doc *yaml.Node, key string) *yaml.Node {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package preset
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockAssetBundle() *AssetBundle {
+	return &AssetBundle{
+		Root: "dashboard_export_20230801T120000",
+		Files: map[string][]byte{
+			"metadata.yaml":                  []byte("version: 1.0.0\ntype: Dashboard\ntimestamp: '2023-08-01T12:00:00+00:00'\n"),
+			"databases/warehouse.yaml":       []byte("database_name: warehouse\nsqlalchemy_uri: postgresql://staging@db-staging/warehouse\nuuid: 1d2c0ee4-2c1a-4d9e-9c8e-0c8b7ab2d0f1\n"),
+			"datasets/warehouse/orders.yaml": []byte("table_name: orders\nuuid: 7a9f3c4e-b5a8-4c52-8d4e-5f2a9e6b1c3d\ndatabase_uuid: 1d2c0ee4-2c1a-4d9e-9c8e-0c8b7ab2d0f1\n"),
+			"dashboards/Sales_7.yaml":        []byte("dashboard_title: Sales\nuuid: 3e8b7c2a-9f1d-4a6e-b2c5-8d7f6e5a4b3c\n"),
+		},
+	}
+}
+
+func TestAssetBundle_ZipRoundTrip(t *testing.T) {
+	bundle := mockAssetBundle()
+
+	var archive bytes.Buffer
+	err := bundle.WriteZip(&archive)
+	assert.NoError(t, err)
+
+	read, err := ReadAssetBundle(archive.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, bundle, read)
+
+	kind, err := read.Kind()
+	assert.NoError(t, err)
+	assert.Equal(t, ASSET_DASHBOARD, kind)
+	assert.Equal(t, []string{"datasets/warehouse/orders.yaml"}, read.Paths(ASSET_DATASET))
+}
+
+func TestReadAssetBundle_RejectsUnsafePaths(t *testing.T) {
+	for _, name := range []string{"root/../../x.yaml", "root/../other/x.yaml", "/root/x.yaml", `root\..\x.yaml`} {
+		var archive bytes.Buffer
+		writer := zip.NewWriter(&archive)
+		file, _ := writer.Create(name)
+		file.Write([]byte("x: 1\n"))
+		writer.Close()
+
+		_, err := ReadAssetBundle(archive.Bytes())
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAssetBundle_RewriteDatabaseURIs(t *testing.T) {
+	bundle := mockAssetBundle()
+
+	err := bundle.RewriteDatabaseURIs(map[string]string{"warehouse": "postgresql://prod@db-prod/warehouse"})
+	assert.NoError(t, err)
+	assert.Equal(t, "database_name: warehouse\nsqlalchemy_uri: postgresql://prod@db-prod/warehouse\nuuid: 1d2c0ee4-2c1a-4d9e-9c8e-0c8b7ab2d0f1\n", string(bundle.Files["databases/warehouse.yaml"]))
+}
+
+func TestAssetBundle_RewriteUUIDs(t *testing.T) {
+	bundle := mockAssetBundle()
+
+	bundle.RewriteUUIDs(map[string]string{"1d2c0ee4-2c1a-4d9e-9c8e-0c8b7ab2d0f1": "5b6c7d8e-0000-4000-8000-000000000001"})
+	assert.Contains(t, string(bundle.Files["databases/warehouse.yaml"]), "uuid: 5b6c7d8e-0000-4000-8000-000000000001")
+	assert.Contains(t, string(bundle.Files["datasets/warehouse/orders.yaml"]), "database_uuid: 5b6c7d8e-0000-4000-8000-000000000001")
+}
+
+func TestAssetBundle_RewriteUUIDs_Swap(t *testing.T) {
+	bundle := AssetBundle{Root: "export", Files: map[string][]byte{
+		"charts/a.yaml": []byte("uuid: aaaaaaaa-0000-4000-8000-000000000001\ndataset_uuid: bbbbbbbb-0000-4000-8000-000000000002\n"),
+	}}
+
+	// Runs often enough to hit different map iteration orders
+	for i := 0; i < 20; i++ {
+		bundle.RewriteUUIDs(map[string]string{
+			"aaaaaaaa-0000-4000-8000-000000000001": "bbbbbbbb-0000-4000-8000-000000000002",
+			"bbbbbbbb-0000-4000-8000-000000000002": "aaaaaaaa-0000-4000-8000-000000000001",
+		})
+		expected := "uuid: bbbbbbbb-0000-4000-8000-000000000002\ndataset_uuid: aaaaaaaa-0000-4000-8000-000000000001\n"
+		if i%2 == 1 {
+			expected = "uuid: aaaaaaaa-0000-4000-8000-000000000001\ndataset_uuid: bbbbbbbb-0000-4000-8000-000000000002\n"
+		}
+		assert.Equal(t, expected, string(bundle.Files["charts/a.yaml"]))
+	}
+}
+
+func TestExportAssets_SuccessfulResponse(t *testing.T) {
+	var archive bytes.Buffer
+	mockAssetBundle().WriteZip(&archive)
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with a ZIP export
+		assert.Equal(t, "/api/v1/dashboard/export/", r.URL.Path)
+		assert.Equal(t, "!(7,8)", r.URL.Query().Get("q"))
+		w.WriteHeader(http.StatusOK)
+		w.Write(archive.Bytes())
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	bundle, err := superset.ExportAssets(context.Background(), ASSET_DASHBOARD, []int{7, 8})
+	assert.NoError(t, err)
+	assert.Equal(t, "dashboard_export_20230801T120000", bundle.Root)
+	assert.Len(t, bundle.Files, 4)
+}
+
+func TestExportAssets_InvalidKind(t *testing.T) {
+	superset := &SupersetClient{BaseURL: "mockBaseURL"}
+
+	_, err := superset.ExportAssets(context.Background(), "invalid", []int{7})
+	assert.EqualError(t, err, "invalid asset kind")
+}
+
+func TestImportAssets_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/dashboard/import/", r.URL.Path)
+		assert.Equal(t, "true", r.FormValue("overwrite"))
+		assert.Equal(t, `{"databases/warehouse.yaml":"secret"}`, r.FormValue("passwords"))
+
+		file, _, err := r.FormFile("formData")
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(file)
+		bundle, err := ReadAssetBundle(content)
+		assert.NoError(t, err)
+		assert.Len(t, bundle.Files, 4)
+
+		// Simulate successful response
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message": "OK"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	err := superset.ImportAssets(context.Background(), mockAssetBundle(), true, map[string]string{"databases/warehouse.yaml": "secret"})
+	assert.NoError(t, err)
+}
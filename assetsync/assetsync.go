@@ -0,0 +1,437 @@
+// Package assetsync keeps the Superset assets of a Preset workspace in a directory
+// of YAML files, so they can be reviewed and versioned like code.
+//
+// Pull writes every asset of a workspace to a directory tree that mirrors the layout
+// of a Superset export (databases/, datasets/, charts/, dashboards/, queries/). Files
+// are normalized with sorted keys and without volatile fields, so pulling an unchanged
+// workspace twice produces no diff. Push uploads the tree back, refusing to overwrite
+// assets that changed remotely since the last pull unless forced.
+package assetsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Name of the file, at the root of the synced directory, that records the state of the last pull
+const StateFile = ".preset-sync.json"
+
+// Top-level keys that change on every export and are dropped from pulled files, by file path
+var VolatileFields = map[string][]string{
+	"metadata.yaml": {"timestamp"},
+}
+
+// Matches Jinja-style placeholders such as {{ env }} or {{env}}
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Options controls how a directory is pushed to a workspace
+type Options struct {
+	// Values substituted for {{ name }} placeholders in the files before upload.
+	// Placeholders whose name is not a key are left as they are, since Superset
+	// itself uses Jinja in SQL and chart parameters.
+	Vars map[string]string
+	// Database passwords keyed by file path, e.g. databases/warehouse.yaml
+	Passwords map[string]string
+	// Overwrite remote assets even when they changed since the last pull
+	Force bool
+}
+
+// ErrNoState is returned by Push when dir has no state file to check remote changes against,
+// i.e. it was never pulled, and the workspace already has assets the push could overwrite
+var ErrNoState = errors.New("no sync state: pull the workspace first or push with Force")
+
+// Asset kinds whose directories Push uploads
+var assetKinds = []preset.AssetKind{
+	preset.ASSET_DATABASE,
+	preset.ASSET_DATASET,
+	preset.ASSET_CHART,
+	preset.ASSET_DASHBOARD,
+	preset.ASSET_SAVED_QUERY,
+}
+
+// ConflictError is returned by Push when remote assets changed since the last pull
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("remote assets changed since the last pull: %s", strings.Join(e.Paths, ", "))
+}
+
+// state records the hash of every remote file as of the last pull or push
+type state struct {
+	Files map[string]string `json:"files"`
+}
+
+// Writes every asset of the workspace to dir, removing files of assets that no longer exist remotely.
+// Files are written with the remote values, so placeholders edited into them are overwritten.
+func Pull(ctx context.Context, client *preset.SupersetClient, dir string) error {
+	files, err := exportNormalized(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	previous, err := readState(dir)
+	if err != nil {
+		return err
+	}
+
+	for name := range previous.Files {
+		if _, found := files[name]; found {
+			continue
+		}
+
+		target, err := localPath(dir, name)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(target)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	for name, content := range files {
+		target, err := localPath(dir, name)
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0755)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(target, content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeState(dir, files)
+}
+
+// Uploads the assets in dir to the workspace, overwriting the remote versions.
+//
+// Only metadata.yaml and the .yaml files under the asset directories are uploaded; anything
+// else in dir, such as a README or a .git directory, is left out. Unless opts.Force is set, a
+// dir that was never pulled can only be pushed to a workspace without assets, since there is
+// no state to tell remote changes from local ones (see ErrNoState).
+func Push(ctx context.Context, client *preset.SupersetClient, dir string, opts Options) error {
+	bundle := preset.AssetBundle{
+		Root:  fmt.Sprintf("assets_export_%s", time.Now().UTC().Format("20060102T150405")),
+		Files: map[string][]byte{},
+	}
+
+	err := filepath.WalkDir(dir, func(target string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if target != dir && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, target)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if !isAssetFile(name) {
+			return nil
+		}
+
+		content, err := os.ReadFile(target)
+		if err != nil {
+			return err
+		}
+
+		bundle.Files[name] = Render(content, opts.Vars)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	metadata, err := restoreMetadata(bundle.Files["metadata.yaml"])
+	if err != nil {
+		return err
+	}
+	bundle.Files["metadata.yaml"] = metadata
+
+	if !opts.Force {
+		err = checkConflicts(ctx, client, dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.ImportAssets(ctx, &bundle, true, opts.Passwords)
+	if err != nil {
+		return err
+	}
+
+	// Record what the workspace looks like now, so the next push doesn't flag our own changes
+	files, err := exportNormalized(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	return writeState(dir, files)
+}
+
+// Rewrites a YAML document with its mapping keys in sorted order and volatile fields removed
+func Normalize(name string, content []byte) ([]byte, error) {
+	doc := yaml.Node{}
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(doc.Content) == 0 {
+		return content, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind == yaml.MappingNode {
+		for _, key := range VolatileFields[name] {
+			removeKey(root, key)
+		}
+	}
+	sortKeys(root)
+
+	return encode(&doc)
+}
+
+// Substitutes vars for the {{ name }} placeholders in content
+func Render(content []byte, vars map[string]string) []byte {
+	if len(vars) == 0 {
+		return content
+	}
+
+	return placeholderPattern.ReplaceAllFunc(content, func(placeholder []byte) []byte {
+		name := placeholderPattern.FindSubmatch(placeholder)[1]
+		if value, found := vars[string(name)]; found {
+			return []byte(value)
+		}
+		return placeholder
+	})
+}
+
+// Fails with a ConflictError when a remote asset no longer matches what the last pull saw
+func checkConflicts(ctx context.Context, client *preset.SupersetClient, dir string) error {
+	previous, err := readState(dir)
+	if err != nil {
+		return err
+	}
+
+	files, err := exportNormalized(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(filepath.Join(dir, StateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		for name := range files {
+			if name != "metadata.yaml" {
+				return ErrNoState
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	conflicts := []string{}
+	for name, content := range files {
+		if previous.Files[name] != hash(content) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	for name := range previous.Files {
+		if _, found := files[name]; !found {
+			conflicts = append(conflicts, name)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return &ConflictError{Paths: conflicts}
+	}
+
+	return nil
+}
+
+// Reports whether the file name of the tree is part of the bundle Push uploads
+func isAssetFile(name string) bool {
+	if name == "metadata.yaml" {
+		return true
+	}
+	if path.Ext(name) != ".yaml" {
+		return false
+	}
+
+	for _, kind := range assetKinds {
+		if strings.HasPrefix(name, kind.Directory()+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func exportNormalized(ctx context.Context, client *preset.SupersetClient) (map[string][]byte, error) {
+	bundle, err := client.ExportAllAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for name, content := range bundle.Files {
+		normalized, err := Normalize(name, content)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = normalized
+	}
+
+	return files, nil
+}
+
+// Puts back the export timestamp that Normalize strips, which the import endpoint expects
+func restoreMetadata(content []byte) ([]byte, error) {
+	metadata := map[string]interface{}{}
+	err := yaml.Unmarshal(content, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("metadata.yaml: %w", err)
+	}
+
+	if _, found := metadata["version"]; !found {
+		metadata["version"] = "1.0.0"
+	}
+	if _, found := metadata["type"]; !found {
+		metadata["type"] = "assets"
+	}
+	metadata["timestamp"] = time.Now().UTC().Format(time.RFC3339)
+
+	return encode(metadata)
+}
+
+// Returns the path of the file name of the tree under dir, refusing names that would land outside of it
+func localPath(dir string, name string) (string, error) {
+	unsafe := strings.Contains(name, `\`) || !filepath.IsLocal(filepath.FromSlash(name))
+	for _, element := range strings.Split(name, "/") {
+		unsafe = unsafe || element == ".."
+	}
+	if unsafe {
+		return "", fmt.Errorf("refusing to write %s outside of %s", name, dir)
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
+
+func readState(dir string) (*state, error) {
+	s := state{Files: map[string]string{}}
+
+	content, err := os.ReadFile(filepath.Join(dir, StateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", StateFile, err)
+	}
+
+	return &s, nil
+}
+
+func writeState(dir string, files map[string][]byte) error {
+	s := state{Files: map[string]string{}}
+	for name, content := range files {
+		s.Files[name] = hash(content)
+	}
+
+	// encoding/json sorts map keys, which keeps the state file diff-friendly too
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, StateFile), append(content, '\n'), 0644)
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func encode(value interface{}) ([]byte, error) {
+	var buf strings.Builder
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(buf.String()), nil
+}
+
+func removeKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func sortKeys(node *yaml.Node) {
+	for _, child := range node.Content {
+		sortKeys(child)
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i][0].Value < pairs[j][0].Value
+	})
+
+	node.Content = node.Content[:0]
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+}
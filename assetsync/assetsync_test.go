@@ -0,0 +1,208 @@
+package assetsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+// Serves bundle on the export endpoint and stores uploads made to the import endpoint in imported
+func mockWorkspace(t *testing.T, bundle *preset.AssetBundle, imported *preset.AssetBundle) (*preset.SupersetClient, func()) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/assets/export/":
+			var archive bytes.Buffer
+			bundle.WriteZip(&archive)
+			w.WriteHeader(http.StatusOK)
+			w.Write(archive.Bytes())
+		case "/api/v1/assets/import/":
+			file, _, err := r.FormFile("bundle")
+			assert.NoError(t, err)
+			content, _ := io.ReadAll(file)
+			upload, err := preset.ReadAssetBundle(content)
+			assert.NoError(t, err)
+			*imported = *upload
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"message": "OK"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	client := &preset.PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	return &preset.SupersetClient{BaseURL: mockServer.URL, Preset: client}, mockServer.Close
+}
+
+func mockBundle() *preset.AssetBundle {
+	return &preset.AssetBundle{
+		Root: "assets_export_20230801T120000",
+		Files: map[string][]byte{
+			"metadata.yaml":            []byte("version: 1.0.0\ntype: assets\ntimestamp: '2023-08-01T12:00:00+00:00'\n"),
+			"databases/warehouse.yaml": []byte("sqlalchemy_uri: postgresql://staging@db/warehouse\ndatabase_name: warehouse\nextra:\n  metadata_cache_timeout: {}\n  allows_virtual_table_explore: true\n"),
+		},
+	}
+}
+
+func TestPull_WritesNormalizedTree(t *testing.T) {
+	dir := t.TempDir()
+	client, closeServer := mockWorkspace(t, mockBundle(), &preset.AssetBundle{})
+	defer closeServer()
+
+	err := Pull(context.Background(), client, dir)
+	assert.NoError(t, err)
+
+	metadata, err := os.ReadFile(filepath.Join(dir, "metadata.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "type: assets\nversion: 1.0.0\n", string(metadata))
+
+	database, err := os.ReadFile(filepath.Join(dir, "databases", "warehouse.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "database_name: warehouse\nextra:\n  allows_virtual_table_explore: true\n  metadata_cache_timeout: {}\nsqlalchemy_uri: postgresql://staging@db/warehouse\n", string(database))
+
+	_, err = os.Stat(filepath.Join(dir, StateFile))
+	assert.NoError(t, err)
+}
+
+func TestPull_RemovesDeletedAssets(t *testing.T) {
+	dir := t.TempDir()
+	bundle := mockBundle()
+	client, closeServer := mockWorkspace(t, bundle, &preset.AssetBundle{})
+	defer closeServer()
+
+	err := Pull(context.Background(), client, dir)
+	assert.NoError(t, err)
+
+	delete(bundle.Files, "databases/warehouse.yaml")
+	err = Pull(context.Background(), client, dir)
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "databases", "warehouse.yaml"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPush_RendersTemplates(t *testing.T) {
+	dir := t.TempDir()
+	imported := preset.AssetBundle{}
+	client, closeServer := mockWorkspace(t, mockBundle(), &imported)
+	defer closeServer()
+
+	err := Pull(context.Background(), client, dir)
+	assert.NoError(t, err)
+
+	template := "database_name: warehouse\nsqlalchemy_uri: postgresql://{{ env }}@db/warehouse\nextra: '{{ current_username() }}'\n"
+	err = os.WriteFile(filepath.Join(dir, "databases", "warehouse.yaml"), []byte(template), 0644)
+	assert.NoError(t, err)
+
+	err = Push(context.Background(), client, dir, Options{Vars: map[string]string{"env": "prod"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "database_name: warehouse\nsqlalchemy_uri: postgresql://prod@db/warehouse\nextra: '{{ current_username() }}'\n", string(imported.Files["databases/warehouse.yaml"]))
+	assert.Contains(t, string(imported.Files["metadata.yaml"]), "timestamp:")
+	assert.NotContains(t, imported.Files, StateFile)
+}
+
+func TestPush_DetectsRemoteChanges(t *testing.T) {
+	dir := t.TempDir()
+	bundle := mockBundle()
+	imported := preset.AssetBundle{}
+	client, closeServer := mockWorkspace(t, bundle, &imported)
+	defer closeServer()
+
+	err := Pull(context.Background(), client, dir)
+	assert.NoError(t, err)
+
+	bundle.Files["databases/warehouse.yaml"] = []byte("database_name: warehouse\nsqlalchemy_uri: postgresql://other@db/warehouse\n")
+
+	err = Push(context.Background(), client, dir, Options{})
+	conflict := &ConflictError{}
+	assert.True(t, errors.As(err, &conflict))
+	assert.Equal(t, []string{"databases/warehouse.yaml"}, conflict.Paths)
+	assert.Empty(t, imported.Files)
+
+	err = Push(context.Background(), client, dir, Options{Force: true})
+	assert.NoError(t, err)
+	assert.Len(t, imported.Files, 2)
+}
+
+func TestPull_RefusesPathsOutsideDir(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "assets")
+	os.Mkdir(dir, 0755)
+	victim := filepath.Join(parent, "victim.yaml")
+	os.WriteFile(victim, []byte("keep: me\n"), 0644)
+	os.WriteFile(filepath.Join(dir, StateFile), []byte(`{"files": {"../victim.yaml": "0000"}}`), 0644)
+
+	client, closeServer := mockWorkspace(t, mockBundle(), &preset.AssetBundle{})
+	defer closeServer()
+
+	err := Pull(context.Background(), client, dir)
+	assert.Error(t, err)
+
+	_, err = os.Stat(victim)
+	assert.NoError(t, err)
+}
+
+func TestPush_UploadsOnlyAssetFiles(t *testing.T) {
+	dir := t.TempDir()
+	imported := preset.AssetBundle{}
+	client, closeServer := mockWorkspace(t, mockBundle(), &imported)
+	defer closeServer()
+
+	err := Pull(context.Background(), client, dir)
+	assert.NoError(t, err)
+
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Assets\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "databases", "warehouse.yaml~"), []byte("backup: true\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, ".git", "databases"), 0755)
+	os.WriteFile(filepath.Join(dir, ".git", "databases", "x.yaml"), []byte("x: 1\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "notes"), 0755)
+	os.WriteFile(filepath.Join(dir, "notes", "todo.yaml"), []byte("x: 1\n"), 0644)
+
+	err = Push(context.Background(), client, dir, Options{})
+	assert.NoError(t, err)
+	assert.Len(t, imported.Files, 2)
+	assert.Contains(t, imported.Files, "metadata.yaml")
+	assert.Contains(t, imported.Files, "databases/warehouse.yaml")
+}
+
+func TestPush_WithoutState(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "databases"), 0755)
+	os.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte("type: assets\nversion: 1.0.0\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "databases", "warehouse.yaml"), []byte("database_name: warehouse\n"), 0644)
+
+	imported := preset.AssetBundle{}
+	client, closeServer := mockWorkspace(t, mockBundle(), &imported)
+	defer closeServer()
+
+	err := Push(context.Background(), client, dir, Options{})
+	assert.True(t, errors.Is(err, ErrNoState))
+	assert.Empty(t, imported.Files)
+
+	err = Push(context.Background(), client, dir, Options{Force: true})
+	assert.NoError(t, err)
+	assert.Len(t, imported.Files, 2)
+
+	// A workspace without assets has nothing to overwrite
+	empty := &preset.AssetBundle{Root: "assets_export_20230801T120000", Files: map[string][]byte{"metadata.yaml": []byte("version: 1.0.0\ntype: assets\n")}}
+	client, closeEmpty := mockWorkspace(t, empty, &imported)
+	defer closeEmpty()
+
+	os.Remove(filepath.Join(dir, StateFile))
+	err = Push(context.Background(), client, dir, Options{})
+	assert.NoError(t, err)
+}
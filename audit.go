@@ -0,0 +1,179 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// How often StreamAuditEvents polls the audit log when it is given a non-positive interval
+const defaultAuditPollInterval = 30 * time.Second
+
+// Returns one page of the team's audit log, oldest events first
+func (c *PresetClient) ListAuditEvents(ctx context.Context, teamID int, filter AuditEventFilter, authToken *string) (*AuditEventPage, error) {
+	query := url.Values{}
+	if !filter.Since.IsZero() {
+		query.Set("start_time", filter.Since.UTC().Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("end_time", filter.Until.UTC().Format(time.RFC3339))
+	}
+	if filter.Actor != "" {
+		query.Set("actor", filter.Actor)
+	}
+	if filter.Action != "" {
+		query.Set("action", filter.Action)
+	}
+	if filter.WorkspaceID != 0 {
+		query.Set("workspace_id", strconv.Itoa(filter.WorkspaceID))
+	}
+	if filter.Cursor != "" {
+		query.Set("cursor", filter.Cursor)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	req, err := http.NewRequestWithContext(withOperation(ctx, "ListAuditEvents", teamID, 0), "GET", fmt.Sprintf("%s/v1/teams/%d/audit-logs?%s", c.BaseURL, teamID, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	aer := AuditEventResponse{}
+	err = c.doDecode(req, authToken, &aer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditEventPage{Events: aer.Payload, NextCursor: aer.Meta.NextCursor}, nil
+}
+
+// Polls the team's audit log every interval and passes each new event to handle, resuming from the
+// checkpoint kept in store. The checkpoint is saved after every page, so an event is only handed out
+// again if handle failed or the process stopped before its page was fully handled. Runs until ctx is
+// cancelled, which also aborts a request in flight, or handle returns an error. A non-positive interval
+// polls every 30 seconds.
+func (c *PresetClient) StreamAuditEvents(ctx context.Context, teamID int, filter AuditEventFilter, store AuditCheckpointStore, interval time.Duration, handle func(AuditEvent) error, authToken *string) error {
+	if interval <= 0 {
+		interval = defaultAuditPollInterval
+	}
+
+	checkpoint, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	for {
+		filter.Cursor = checkpoint.Cursor
+
+		page, err := c.ListAuditEvents(ctx, teamID, filter, authToken)
+		if err != nil {
+			return err
+		}
+
+		events := unhandledEvents(page.Events, checkpoint)
+		for _, event := range events {
+			err = handle(event)
+			if err != nil {
+				return err
+			}
+			checkpoint.LastEventID = event.ID
+			checkpoint.LastEventTime = event.Timestamp
+		}
+
+		if page.NextCursor != "" && page.NextCursor != checkpoint.Cursor {
+			checkpoint.Cursor = page.NextCursor
+			checkpoint.LastEventID = ""
+			checkpoint.LastEventTime = Timestamp{}
+		}
+
+		err = store.Save(checkpoint)
+		if err != nil {
+			return err
+		}
+
+		// Keep paging without waiting while there is a backlog
+		if page.NextCursor != "" && len(page.Events) > 0 && page.NextCursor != filter.Cursor {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Returns the events of a page that come after the checkpoint. A caught-up cursor is polled again,
+// so whatever was handed out from it before is skipped: the events up to the last one handled or,
+// if that one is no longer on the page, those that happened before it.
+func unhandledEvents(events []AuditEvent, checkpoint AuditCheckpoint) []AuditEvent {
+	if checkpoint.LastEventID == "" {
+		return events
+	}
+
+	for i, event := range events {
+		if event.ID == checkpoint.LastEventID {
+			return events[i+1:]
+		}
+	}
+
+	if checkpoint.LastEventTime.IsZero() {
+		return events
+	}
+	unhandled := []AuditEvent{}
+	for _, event := range events {
+		if !event.Timestamp.Time.Before(checkpoint.LastEventTime.Time) {
+			unhandled = append(unhandled, event)
+		}
+	}
+	return unhandled
+}
+
+// AuditCheckpointStore persists how far StreamAuditEvents got through the audit log
+type AuditCheckpointStore interface {
+	Load() (AuditCheckpoint, error)
+	Save(AuditCheckpoint) error
+}
+
+// FileCheckpointStore keeps the audit log checkpoint in a JSON file at the given path
+type FileCheckpointStore string
+
+func (f FileCheckpointStore) Load() (AuditCheckpoint, error) {
+	checkpoint := AuditCheckpoint{}
+
+	content, err := os.ReadFile(string(f))
+	if errors.Is(err, fs.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return checkpoint, err
+	}
+
+	err = json.Unmarshal(content, &checkpoint)
+	return checkpoint, err
+}
+
+func (f FileCheckpointStore) Save(checkpoint AuditCheckpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated checkpoint behind
+	tmp := string(f) + ".tmp"
+	err = os.WriteFile(tmp, content, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, string(f))
+}
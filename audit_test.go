@@ -0,0 +1,241 @@
+package preset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAuditEvents_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/teams/831/audit-logs", r.URL.Path)
+		assert.Equal(t, "2023-08-01T00:00:00Z", r.URL.Query().Get("start_time"))
+		assert.Equal(t, "foo@example.com", r.URL.Query().Get("actor"))
+		assert.Equal(t, "2", r.URL.Query().Get("workspace_id"))
+		assert.Empty(t, r.URL.Query().Get("end_time"))
+
+		// Simulate successful response with mock data
+		response := []byte(`{
+			"payload": [
+				{
+					"id": "evt-1",
+					"timestamp": "2023-08-01T12:00:00Z",
+					"action": "workspace_membership.update",
+					"actor": {"id": 456, "email": "foo@example.com"},
+					"team_id": 831,
+					"workspace_id": 2,
+					"details": {"role_identifier": "PresetAlpha"}
+				}
+			],
+			"meta": {"next_cursor": "cursor-1"}
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	filter := AuditEventFilter{
+		Since:       time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+		Actor:       "foo@example.com",
+		WorkspaceID: 2,
+	}
+	page, err := client.ListAuditEvents(context.Background(), 831, filter, nil)
+	assert.NoError(t, err)
+	assert.Len(t, page.Events, 1)
+	assert.Equal(t, "foo@example.com", page.Events[0].Actor.Email)
+	assert.Equal(t, 2, *page.Events[0].WorkspaceID)
+	assert.True(t, time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC).Equal(page.Events[0].Timestamp.Time))
+	assert.Equal(t, "cursor-1", page.NextCursor)
+}
+
+func TestStreamAuditEvents_ResumesFromCheckpoint(t *testing.T) {
+	polls := 0
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"payload": [{"id": "evt-1"}, {"id": "evt-2"}], "meta": {"next_cursor": "cursor-1"}}`))
+		case "cursor-1":
+			// The cursor stays put once caught up and the page grows as new events arrive
+			polls++
+			if polls == 1 {
+				w.Write([]byte(`{"payload": [{"id": "evt-3"}], "meta": {"next_cursor": "cursor-1"}}`))
+			} else {
+				w.Write([]byte(`{"payload": [{"id": "evt-3"}, {"id": "evt-4"}], "meta": {"next_cursor": "cursor-1"}}`))
+			}
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	store := FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	done := errors.New("done")
+	handled := []string{}
+	handle := func(event AuditEvent) error {
+		handled = append(handled, event.ID)
+		if event.ID == "evt-4" {
+			return done
+		}
+		return nil
+	}
+
+	err := client.StreamAuditEvents(context.Background(), 831, AuditEventFilter{}, store, time.Millisecond, handle, nil)
+	assert.ErrorIs(t, err, done)
+	assert.Equal(t, []string{"evt-1", "evt-2", "evt-3", "evt-4"}, handled)
+
+	checkpoint, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, AuditCheckpoint{Cursor: "cursor-1", LastEventID: "evt-3"}, checkpoint)
+}
+
+func TestStreamAuditEvents_ResumesFromTimeWhenEventIsGone(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The last event handled, evt-2, is no longer on the page
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": [
+			{"id": "evt-1", "timestamp": "2023-08-01T12:00:00Z"},
+			{"id": "evt-3", "timestamp": "2023-08-01 12:00:02"},
+			{"id": "evt-4", "timestamp": "2023-08-01T14:00:03+02:00"}
+		], "meta": {"next_cursor": "cursor-1"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	store := FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	lastEventTime, err := ParseTimestamp("2023-08-01T12:00:01.5Z")
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(AuditCheckpoint{Cursor: "cursor-1", LastEventID: "evt-2", LastEventTime: lastEventTime}))
+
+	done := errors.New("done")
+	handled := []string{}
+	handle := func(event AuditEvent) error {
+		handled = append(handled, event.ID)
+		if event.ID == "evt-4" {
+			return done
+		}
+		return nil
+	}
+
+	err = client.StreamAuditEvents(context.Background(), 831, AuditEventFilter{}, store, time.Millisecond, handle, nil)
+	assert.ErrorIs(t, err, done)
+	assert.Equal(t, []string{"evt-3", "evt-4"}, handled)
+}
+
+func TestStreamAuditEvents_StopsOnCancel(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate an empty audit log
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": [], "meta": {"next_cursor": ""}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	store := FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	err := client.StreamAuditEvents(ctx, 831, AuditEventFilter{}, store, time.Millisecond, func(AuditEvent) error { return nil }, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStreamAuditEvents_CancelAbortsRequestInFlight(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate an audit log that doesn't answer until the client gives up
+		<-r.Context().Done()
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	store := FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	err := client.StreamAuditEvents(ctx, 831, AuditEventFilter{}, store, time.Millisecond, func(AuditEvent) error { return nil }, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestStreamAuditEvents_DefaultsNonPositiveInterval(t *testing.T) {
+	var requests int32
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate an empty audit log
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": [], "meta": {"next_cursor": ""}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	store := FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	err := client.StreamAuditEvents(ctx, 831, AuditEventFilter{}, store, 0, func(AuditEvent) error { return nil }, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestListAuditEvents_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	_, err := client.ListAuditEvents(context.Background(), 831, AuditEventFilter{}, nil)
+	assert.Error(t, err)
+}
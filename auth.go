@@ -1,7 +1,7 @@
 package preset
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,23 +12,18 @@ func (c *PresetClient) GetAccessToken() (*AuthResponse, error) {
 	if c.Auth.TokenName == "" || c.Auth.Secret == "" {
 		return nil, fmt.Errorf("missing API token name and/or secret")
 	}
-	rb, err := json.Marshal(c.Auth)
+	rb, err := c.codec().Marshal(c.Auth)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/v1/auth", c.BaseURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req, nil)
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "GetAccessToken", 0, 0), "POST", fmt.Sprintf("%s/v1/auth", c.BaseURL), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
 
 	ar := AuthResponse{}
-	err = json.Unmarshal(body, &ar)
+	err = c.doDecode(req, nil, &ar)
 	if err != nil {
 		return nil, err
 	}
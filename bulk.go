@@ -0,0 +1,159 @@
+package preset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Number of changes applied at once when BulkOptions.Concurrency isn't set
+const DefaultBulkConcurrency = 4
+
+// ErrSkipped is the error of changes not attempted because an earlier one failed in
+// stop-on-first-error mode
+var ErrSkipped = errors.New("skipped after an earlier failure")
+
+// BulkOptions controls how bulk operations run
+type BulkOptions struct {
+	// Number of changes applied at once; defaults to DefaultBulkConcurrency
+	Concurrency int
+	// Stops starting new changes once one fails. Changes already running are allowed to finish,
+	// and the rest fail with ErrSkipped.
+	StopOnError bool
+}
+
+// BulkResult is the outcome of one change in a bulk operation
+type BulkResult[C any, R any] struct {
+	Change C
+	// What the API returned for a successful change
+	Result *R
+	// Why the change failed. Use errors.As with *HTTPError to get the status the API answered with.
+	Err error
+}
+
+// BulkError is returned by bulk operations when any change failed or was skipped. The results
+// tell which.
+type BulkError struct {
+	Total   int
+	Failed  int
+	Skipped int
+	// Error of the first change that failed, in input order
+	First error
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d of %d changes failed, %d skipped: %v", e.Failed, e.Total, e.Skipped, e.First)
+}
+
+func (e *BulkError) Unwrap() error {
+	return e.First
+}
+
+// WorkspaceRoleChange gives a user a workspace role, named as for UpdateUserWorkspaceRole
+type WorkspaceRoleChange struct {
+	UserID         int
+	RoleIdentifier string
+}
+
+type WorkspaceRoleResult = BulkResult[WorkspaceRoleChange, WorkspaceMembership]
+
+// TeamRoleChange gives a user a team role
+type TeamRoleChange struct {
+	UserID int
+	RoleID TeamRoleEnum
+}
+
+type TeamRoleResult = BulkResult[TeamRoleChange, TeamMembership]
+
+// Applies workspace role changes concurrently. Results are in the order of changes, and a
+// *BulkError is returned alongside them if any change didn't succeed.
+func (c *PresetClient) BulkUpdateWorkspaceRoles(ctx context.Context, teamID int, workspaceID int, changes []WorkspaceRoleChange, opts BulkOptions, authToken *string) ([]WorkspaceRoleResult, error) {
+	return runBulk(ctx, changes, opts, func(ctx context.Context, change WorkspaceRoleChange) (*WorkspaceMembership, error) {
+		return c.updateUserWorkspaceRole(ctx, teamID, workspaceID, change.UserID, change.RoleIdentifier, authToken)
+	})
+}
+
+// Applies team role changes concurrently. Results are in the order of changes, and a
+// *BulkError is returned alongside them if any change didn't succeed.
+func (c *PresetClient) BulkUpdateTeamRoles(ctx context.Context, teamID int, changes []TeamRoleChange, opts BulkOptions, authToken *string) ([]TeamRoleResult, error) {
+	return runBulk(ctx, changes, opts, func(ctx context.Context, change TeamRoleChange) (*TeamMembership, error) {
+		return c.updateUserTeamRole(ctx, teamID, change.UserID, change.RoleID, authToken)
+	})
+}
+
+// Applies each change with apply on a pool of workers. apply is given ctx, so that canceling it
+// aborts the requests in flight as well.
+func runBulk[C any, R any](ctx context.Context, changes []C, opts BulkOptions, apply func(context.Context, C) (*R, error)) ([]BulkResult[C, R], error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+
+	results := make([]BulkResult[C, R], len(changes))
+	for i, change := range changes {
+		results[i].Change = change
+	}
+
+	var (
+		mu      sync.Mutex
+		stopped bool
+		wg      sync.WaitGroup
+	)
+	indexes := make(chan int)
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				mu.Lock()
+				skip := stopped
+				mu.Unlock()
+
+				switch {
+				case ctx.Err() != nil:
+					results[i].Err = ctx.Err()
+					continue
+				case skip:
+					results[i].Err = ErrSkipped
+					continue
+				}
+
+				result, err := apply(ctx, changes[i])
+				results[i].Result = result
+				results[i].Err = err
+				if err != nil && opts.StopOnError {
+					mu.Lock()
+					stopped = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range changes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	bulkErr := &BulkError{Total: len(changes)}
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+			continue
+		case errors.Is(result.Err, ErrSkipped):
+			bulkErr.Skipped++
+		default:
+			bulkErr.Failed++
+			if bulkErr.First == nil {
+				bulkErr.First = result.Err
+			}
+		}
+	}
+	if bulkErr.Failed+bulkErr.Skipped == 0 {
+		return results, nil
+	}
+	return results, bulkErr
+}
@@ -0,0 +1,180 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a mock server updating workspace roles, failing for the given user and tracking the
+// most requests in flight at once
+func bulkServer(t *testing.T, failUserID int, maxInFlight *int) *httptest.Server {
+	var mu sync.Mutex
+	inFlight := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > *maxInFlight {
+			*maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		payload := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		userID := int(payload["user_id"].(float64))
+		if userID == failUserID {
+			// Simulate a 500 internal server error
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"payload": {"user": {"id": %d}, "workspace_role": {"role_identifier": "%s"}}}`, userID, payload["role_identifier"])
+	}))
+}
+
+func TestBulkUpdateWorkspaceRoles_Continue(t *testing.T) {
+	maxInFlight := 0
+	mockServer := bulkServer(t, 3, &maxInFlight)
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	changes := []WorkspaceRoleChange{}
+	for userID := 1; userID <= 12; userID++ {
+		changes = append(changes, WorkspaceRoleChange{UserID: userID, RoleIdentifier: "viewer"})
+	}
+	changes[5].RoleIdentifier = "overlord"
+
+	results, err := client.BulkUpdateWorkspaceRoles(context.Background(), 1, 2, changes, BulkOptions{Concurrency: 3}, nil)
+	assert.Len(t, results, 12)
+	assert.LessOrEqual(t, maxInFlight, 3)
+
+	var bulkErr *BulkError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, 12, bulkErr.Total)
+	assert.Equal(t, 2, bulkErr.Failed)
+	assert.Equal(t, 0, bulkErr.Skipped)
+	assert.Contains(t, bulkErr.First.Error(), "status: 500")
+
+	for i, result := range results {
+		assert.Equal(t, changes[i], result.Change)
+		switch result.Change.UserID {
+		case 3:
+			var httpErr *HTTPError
+			assert.True(t, errors.As(result.Err, &httpErr))
+			assert.Equal(t, http.StatusInternalServerError, httpErr.StatusCode)
+		case 6:
+			assert.Contains(t, result.Err.Error(), "invalid role identifier")
+		default:
+			assert.NoError(t, result.Err)
+			assert.Equal(t, result.Change.UserID, result.Result.User.ID)
+			assert.Equal(t, "PresetReportsOnly", result.Result.WorkspaceRole.RoleIdentifier)
+		}
+	}
+}
+
+func TestBulkUpdateWorkspaceRoles_StopOnError(t *testing.T) {
+	maxInFlight := 0
+	mockServer := bulkServer(t, 1, &maxInFlight)
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	changes := []WorkspaceRoleChange{}
+	for userID := 1; userID <= 20; userID++ {
+		changes = append(changes, WorkspaceRoleChange{UserID: userID, RoleIdentifier: "viewer"})
+	}
+
+	results, err := client.BulkUpdateWorkspaceRoles(context.Background(), 1, 2, changes, BulkOptions{Concurrency: 1, StopOnError: true}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 20 changes failed, 19 skipped")
+	for _, result := range results[1:] {
+		assert.ErrorIs(t, result.Err, ErrSkipped)
+	}
+}
+
+func TestBulkUpdateTeamRoles(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		userID := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		fmt.Fprintf(w, `{"payload": {"user": {"id": %s}, "team_role": {"id": 1, "name": "Admin"}}}`, userID)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	results, err := client.BulkUpdateTeamRoles(context.Background(), 1, []TeamRoleChange{
+		{UserID: 7, RoleID: TEAM_ADMIN},
+		{UserID: 8, RoleID: TEAM_ADMIN},
+	}, BulkOptions{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, results[1].Result.User.ID)
+	assert.Equal(t, "Admin", results[0].Result.TeamRole.Name)
+}
+
+func TestBulkUpdateTeamRoles_Canceled(t *testing.T) {
+	client := &PresetClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := client.BulkUpdateTeamRoles(ctx, 1, []TeamRoleChange{{UserID: 7, RoleID: TEAM_ADMIN}}, BulkOptions{}, nil)
+	assert.Error(t, err)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+}
+
+func TestBulkUpdateTeamRoles_CancelAbortsRequestsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server only notices the client going away once the body is consumed
+		io.ReadAll(r.Body)
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	results, err := client.BulkUpdateTeamRoles(ctx, 1, []TeamRoleChange{{UserID: 7, RoleID: TEAM_ADMIN}}, BulkOptions{}, nil)
+	assert.Error(t, err)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+}
@@ -0,0 +1,313 @@
+package preset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache is a read-through cache of GET responses, shared by the clients it is set on.
+// Responses are keyed by URL and the Authorization header, so callers with different tokens
+// never see each other's data.
+//
+// Only operations listed in TTLs are cached. A cached response is served without a request until
+// its TTL runs out. After that, if the server sent an ETag, it is revalidated with If-None-Match
+// and served again on 304 Not Modified; a TTL of 0 revalidates every time. Query results, query
+// status and audit events are never cached, whatever TTLs says.
+//
+// Any other request invalidates the cached responses from the same host concerning the same
+// team, as well as those not tied to a team such as the list of teams.
+type ResponseCache struct {
+	// TTLs by operation, named as in CallInfo without the preset. prefix, e.g. GetAllWorkspaces
+	TTLs map[string]time.Duration
+	// Most responses kept; the least recently used are evicted past it. Defaults to
+	// DefaultCacheMaxEntries.
+	MaxEntries int
+	// Largest response body cached, in bytes; larger ones are passed through uncached. Defaults to
+	// DefaultCacheMaxEntryBytes.
+	MaxEntryBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	now     func() time.Time
+}
+
+// Limits of a ResponseCache that doesn't set its own
+const (
+	DefaultCacheMaxEntries          = 1000
+	DefaultCacheMaxEntryBytes int64 = 1 << 20
+)
+
+// Operations whose responses change too often, or are too large, to be worth caching
+var uncachedOperations = map[string]bool{
+	"GetQuery":          true,
+	"FetchQueryResults": true,
+	"ListAuditEvents":   true,
+}
+
+type cacheEntry struct {
+	host    string
+	teamID  int
+	header  http.Header
+	body    []byte
+	etag    string
+	expires time.Time
+	used    time.Time
+}
+
+// Returns a cache with the given TTLs by operation
+func NewResponseCache(ttls map[string]time.Duration) *ResponseCache {
+	return &ResponseCache{TTLs: ttls}
+}
+
+// Drops every cached response
+func (c *ResponseCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = nil
+}
+
+// Returns the number of cached responses
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+func (c *ResponseCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// Returns the TTL of an operation, reporting whether its responses are cached at all
+func (c *ResponseCache) ttl(operation string) (time.Duration, bool) {
+	operation = strings.TrimPrefix(operation, "preset.")
+	if uncachedOperations[operation] {
+		return 0, false
+	}
+
+	ttl, ok := c.TTLs[operation]
+	return ttl, ok
+}
+
+func (c *ResponseCache) maxEntryBytes() int64 {
+	if c.MaxEntryBytes > 0 {
+		return c.MaxEntryBytes
+	}
+	return DefaultCacheMaxEntryBytes
+}
+
+// Looks up a cached response, dropping it if it expired and can't be revalidated. Returns a copy
+// of the entry, so it can be used without holding the lock, and whether it is still fresh.
+func (c *ResponseCache) lookup(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[key]
+	if entry == nil {
+		return nil, false
+	}
+
+	now := c.clock()
+	fresh := now.Before(entry.expires)
+	if !fresh && entry.etag == "" {
+		delete(c.entries, key)
+		return nil, false
+	}
+	entry.used = now
+
+	found := *entry
+	return &found, fresh
+}
+
+// Extends the lifetime of a cached response the server confirmed is unchanged
+func (c *ResponseCache) refresh(key string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[key]
+	if entry == nil {
+		return
+	}
+
+	now := c.clock()
+	entry.expires = now.Add(ttl)
+	entry.used = now
+}
+
+// Stores entry under key, making room for it first
+func (c *ResponseCache) store(key string, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]*cacheEntry{}
+	}
+
+	maxEntries := c.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+
+	now := c.clock()
+	if _, found := c.entries[key]; !found && len(c.entries) >= maxEntries {
+		// Expired entries that can't be revalidated go first, then the least recently used
+		for k, e := range c.entries {
+			if !now.Before(e.expires) && e.etag == "" {
+				delete(c.entries, k)
+			}
+		}
+		for len(c.entries) >= maxEntries {
+			oldest := ""
+			for k, e := range c.entries {
+				if oldest == "" || e.used.Before(c.entries[oldest].used) {
+					oldest = k
+				}
+			}
+			delete(c.entries, oldest)
+		}
+	}
+
+	entry.used = now
+	c.entries[key] = entry
+}
+
+// Returns the cache key of a request, hashing its credentials
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.URL.String() + "#" + hex.EncodeToString(sum[:8])
+}
+
+// Drops the cached responses a mutation of the given team on host may have made stale
+func (c *ResponseCache) invalidate(host string, teamID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if entry.host != host {
+			continue
+		}
+		if teamID == 0 || entry.teamID == 0 || entry.teamID == teamID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Returns a Middleware serving GET requests from cache and invalidating it on other requests
+func CacheMiddleware(cache *ResponseCache) Middleware {
+	return cacheMiddleware(cache, 0)
+}
+
+// Returns CacheMiddleware for a client reading at most maxBodySize bytes of a response, 0 for no
+// limit. Larger bodies are left to the client to reject.
+func cacheMiddleware(cache *ResponseCache, maxBodySize int64) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			call := describeCall(req)
+
+			if req.Method != http.MethodGet {
+				// Invalidate on both sides of the mutation, so a GET running alongside it can't
+				// leave a response from before it in the cache
+				cache.invalidate(req.URL.Host, call.TeamID)
+				res, err := next.Do(req)
+				cache.invalidate(req.URL.Host, call.TeamID)
+				return res, err
+			}
+
+			ttl, cached := cache.ttl(call.Operation)
+			if !cached {
+				return next.Do(req)
+			}
+
+			key := cacheKey(req)
+			entry, fresh := cache.lookup(key)
+			if fresh {
+				return entry.response(req), nil
+			}
+
+			if entry != nil {
+				req = req.Clone(req.Context())
+				req.Header.Set("If-None-Match", entry.etag)
+			}
+
+			res, err := next.Do(req)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case res.StatusCode == http.StatusNotModified && entry != nil:
+				res.Body.Close()
+				cache.refresh(key, ttl)
+				return entry.response(req), nil
+			case res.StatusCode != http.StatusOK:
+				return res, nil
+			}
+
+			etag := res.Header.Get("ETag")
+			if ttl <= 0 && etag == "" {
+				return res, nil
+			}
+
+			limit := cache.maxEntryBytes()
+			if maxBodySize > 0 && maxBodySize < limit {
+				limit = maxBodySize
+			}
+			if res.ContentLength > limit {
+				return res, nil
+			}
+
+			// Read one byte past the limit to tell whether the body fits
+			body, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+			if err != nil {
+				res.Body.Close()
+				return nil, err
+			}
+			if int64(len(body)) > limit {
+				// Too large to cache: hand back what was read followed by the rest of the stream
+				res.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(body), res.Body), res.Body}
+				return res, nil
+			}
+			res.Body.Close()
+			res.Body = io.NopCloser(bytes.NewReader(body))
+
+			cache.store(key, &cacheEntry{
+				host:    req.URL.Host,
+				teamID:  call.TeamID,
+				header:  res.Header.Clone(),
+				body:    body,
+				etag:    etag,
+				expires: cache.clock().Add(ttl),
+			})
+
+			return res, nil
+		})
+	}
+}
+
+// Returns a response to req holding the cached body
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
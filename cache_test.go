@@ -0,0 +1,309 @@
+package preset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Counts requests to a mock server by method and path
+type requestCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *requestCounter) add(r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = map[string]int{}
+	}
+	c.counts[r.Method+" "+r.URL.Path]++
+}
+
+func (c *requestCounter) get(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[key]
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	counter := &requestCounter{}
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter.add(r)
+		w.Write([]byte(`{"payload": [{"id": 1, "name": "acme"}]}`))
+	}))
+	defer mockServer.Close()
+
+	now := time.Now()
+	cache := NewResponseCache(map[string]time.Duration{"GetAllTeams": time.Minute})
+	cache.now = func() time.Time { return now }
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Cache:      cache,
+	}
+
+	for i := 0; i < 3; i++ {
+		teams, err := client.GetAllTeams(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", (*teams)[0].Name)
+	}
+	assert.Equal(t, 1, counter.get("GET /v1/teams"))
+
+	// Another token doesn't share the cached response
+	other := "Bearer other"
+	_, err := client.GetAllTeams(&other)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counter.get("GET /v1/teams"))
+
+	now = now.Add(2 * time.Minute)
+	_, err = client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, counter.get("GET /v1/teams"))
+
+	// Operations without a TTL entry aren't cached
+	_, err = client.GetAllWorkspaces(1, nil)
+	assert.NoError(t, err)
+	_, err = client.GetAllWorkspaces(1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counter.get("GET /v1/teams/1/workspaces"))
+}
+
+func TestResponseCache_ETagRevalidation(t *testing.T) {
+	counter := &requestCounter{}
+	notModified := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter.add(r)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"payload": [{"id": 5, "title": "Finance"}]}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		// A TTL of 0 revalidates every time
+		Cache: NewResponseCache(map[string]time.Duration{"GetAllWorkspaces": 0}),
+	}
+
+	for i := 0; i < 3; i++ {
+		workspaces, err := client.GetAllWorkspaces(1, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Finance", (*workspaces)[0].Title)
+	}
+	assert.Equal(t, 3, counter.get("GET /v1/teams/1/workspaces"))
+	assert.Equal(t, 2, notModified)
+}
+
+func TestResponseCache_MutationsInvalidate(t *testing.T) {
+	counter := &requestCounter{}
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter.add(r)
+		if r.Method == "PATCH" {
+			w.Write([]byte(`{"payload": {"id": 5}}`))
+			return
+		}
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	cache := NewResponseCache(map[string]time.Duration{"GetAllTeams": time.Hour, "GetAllWorkspaces": time.Hour})
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Cache:      cache,
+	}
+
+	fetchAll := func() {
+		_, err := client.GetAllTeams(nil)
+		assert.NoError(t, err)
+		_, err = client.GetAllWorkspaces(1, nil)
+		assert.NoError(t, err)
+		_, err = client.GetAllWorkspaces(2, nil)
+		assert.NoError(t, err)
+	}
+
+	fetchAll()
+	assert.Equal(t, 3, cache.Len())
+
+	enabled := true
+	_, err := client.UpdateWorkspaceSettings(1, 5, WorkspaceSettings{AiAssistActivated: &enabled}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+
+	fetchAll()
+	assert.Equal(t, 2, counter.get("GET /v1/teams"))
+	assert.Equal(t, 2, counter.get("GET /v1/teams/1/workspaces"))
+	assert.Equal(t, 1, counter.get("GET /v1/teams/2/workspaces"))
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestResponseCache_NeverCachesQueriesOrAudit(t *testing.T) {
+	counter := &requestCounter{}
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter.add(r)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"payload": [], "result": {"id": 7, "status": "running"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Cache: NewResponseCache(map[string]time.Duration{
+			"GetQuery":        time.Hour,
+			"ListAuditEvents": time.Hour,
+		}),
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	for i := 0; i < 2; i++ {
+		_, err := superset.GetQuery(context.Background(), 7)
+		assert.NoError(t, err)
+		_, err = client.ListAuditEvents(context.Background(), 1, AuditEventFilter{}, nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, counter.get("GET /api/v1/query/7"))
+	assert.Equal(t, 2, counter.get("GET /v1/teams/1/audit-logs"))
+	assert.Equal(t, 0, client.Cache.Len())
+}
+
+func TestResponseCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	counter := &requestCounter{}
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter.add(r)
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	now := time.Now()
+	cache := NewResponseCache(map[string]time.Duration{"GetAllWorkspaces": time.Minute})
+	cache.MaxEntries = 2
+	cache.now = func() time.Time { return now }
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Cache:      cache,
+	}
+
+	get := func(teamID int) {
+		now = now.Add(time.Second)
+		_, err := client.GetAllWorkspaces(teamID, nil)
+		assert.NoError(t, err)
+	}
+
+	get(1)
+	get(2)
+	get(1)
+	get(3)
+	assert.Equal(t, 2, cache.Len())
+
+	// Team 2 was the least recently used, so it was evicted while team 1 is still cached
+	get(1)
+	get(2)
+	assert.Equal(t, 1, counter.get("GET /v1/teams/1/workspaces"))
+	assert.Equal(t, 2, counter.get("GET /v1/teams/2/workspaces"))
+
+	// Expired entries without an ETag are dropped when looked up
+	now = now.Add(time.Hour)
+	get(2)
+	assert.Equal(t, 3, counter.get("GET /v1/teams/2/workspaces"))
+	assert.LessOrEqual(t, cache.Len(), 2)
+}
+
+func TestResponseCache_SkipsLargeBodies(t *testing.T) {
+	counter := &requestCounter{}
+	members := `{"payload": [` + strings.Repeat(`{"user": {"id": 1}},`, 99) + `{"user": {"id": 2}}]}`
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter.add(r)
+		// Stream the body so its length isn't known up front
+		w.(http.Flusher).Flush()
+		w.Write([]byte(members))
+	}))
+	defer mockServer.Close()
+
+	cache := NewResponseCache(map[string]time.Duration{"GetWorkspaceMembership": time.Minute})
+	cache.MaxEntryBytes = 100
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Cache:      cache,
+	}
+
+	// Bodies over the entry limit are passed through whole, without being cached
+	for i := 0; i < 2; i++ {
+		memberships, err := client.GetWorkspaceMembership(1, 2, nil)
+		assert.NoError(t, err)
+		assert.Len(t, *memberships, 100)
+	}
+	assert.Equal(t, 2, counter.get("GET /v1/teams/1/workspaces/2/memberships"))
+	assert.Equal(t, 0, cache.Len())
+
+	// The client's own body limit still applies
+	cache.MaxEntryBytes = 0
+	client.MaxBodySize = 100
+	_, err := client.GetWorkspaceMembership(1, 2, nil)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestResponseCache_ConcurrentRevalidation(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"payload": [{"id": 5, "title": "Finance"}]}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Cache:      NewResponseCache(map[string]time.Duration{"GetAllWorkspaces": 0}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				workspaces, err := client.GetAllWorkspaces(1, nil)
+				assert.NoError(t, err)
+				assert.Equal(t, "Finance", (*workspaces)[0].Title)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, client.Cache.Len())
+}
@@ -1,8 +1,10 @@
 package preset
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -17,6 +19,24 @@ type PresetClient struct {
 	HTTPClient *http.Client
 	Token      string
 	Auth       AuthStruct
+	// Logger receives a record for every request when set. Secrets are always redacted.
+	Logger *slog.Logger
+	// Number of bytes of request and response bodies to include in log records; 0 leaves them out
+	LogBodyLimit int
+	// Instrumentation is notified of every call and each of its attempts when set, e.g. to trace them
+	Instrumentation Instrumentation
+	// Retry resends requests that fail transiently when set
+	Retry *RetryPolicy
+	// RateLimiter paces every request, including retries, when set
+	RateLimiter RateLimiter
+	// Cache serves GET responses from memory when set; see ResponseCache
+	Cache *ResponseCache
+	// Middlewares wrap every request, including the auth call; see Middleware for the order
+	Middlewares []Middleware
+	// Codec encodes payloads and decodes responses; defaults to JSONCodec
+	Codec Codec
+	// Largest response body read, in bytes; 0 means no limit. Longer bodies fail with ErrBodyTooLarge.
+	MaxBodySize int64
 }
 
 // AuthStruct
@@ -33,8 +53,87 @@ type AuthResponse struct {
 	Payload AuthPayload `json:"payload"`
 }
 
+// Option configures a PresetClient created by NewClient. Options are applied before the client
+// authenticates, so the auth call already goes through the configured middlewares.
+type Option func(*PresetClient)
+
+// Sends requests with client instead of the default one, which times out after 10 seconds
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *PresetClient) {
+		c.HTTPClient = client
+	}
+}
+
+// Appends middlewares to PresetClient.Middlewares
+func WithMiddlewares(middlewares ...Middleware) Option {
+	return func(c *PresetClient) {
+		c.Middlewares = append(c.Middlewares, middlewares...)
+	}
+}
+
+// Sets PresetClient.Retry
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *PresetClient) {
+		c.Retry = &policy
+	}
+}
+
+// Sets PresetClient.RateLimiter
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *PresetClient) {
+		c.RateLimiter = limiter
+	}
+}
+
+// Sets PresetClient.Cache
+func WithCache(cache *ResponseCache) Option {
+	return func(c *PresetClient) {
+		c.Cache = cache
+	}
+}
+
+// Sets PresetClient.Logger and PresetClient.LogBodyLimit
+func WithLogger(logger *slog.Logger, bodyLimit int) Option {
+	return func(c *PresetClient) {
+		c.Logger = logger
+		c.LogBodyLimit = bodyLimit
+	}
+}
+
+// Sets PresetClient.Instrumentation
+func WithInstrumentation(instrumentation Instrumentation) Option {
+	return func(c *PresetClient) {
+		c.Instrumentation = instrumentation
+	}
+}
+
+// Sets PresetClient.Codec
+func WithCodec(codec Codec) Option {
+	return func(c *PresetClient) {
+		c.Codec = codec
+	}
+}
+
+// Sets PresetClient.MaxBodySize
+func WithMaxBodySize(size int64) Option {
+	return func(c *PresetClient) {
+		c.MaxBodySize = size
+	}
+}
+
+// HTTPError is returned when an API answers with a non-2xx status. Use errors.As to inspect it.
+type HTTPError struct {
+	StatusCode int
+	// Response body, cut at the client's MaxBodySize
+	Body []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
+}
+
 // NewClient
-func NewClient(host, tokenName, secret *string) (*PresetClient, error) {
+func NewClient(host, tokenName, secret *string, opts ...Option) (*PresetClient, error) {
 	c := PresetClient{
 		HTTPClient: &http.Client{Timeout: 10 * time.Second},
 		BaseURL: APIURL,
@@ -44,6 +143,10 @@ func NewClient(host, tokenName, secret *string) (*PresetClient, error) {
 		c.BaseURL = *host
 	}
 
+	for _, opt := range opts {
+		opt(&c)
+	}
+
 	// If tokenName or secret not provided, return empty client
 	if tokenName == nil || secret == nil {
 		return &c, nil
@@ -65,6 +168,17 @@ func NewClient(host, tokenName, secret *string) (*PresetClient, error) {
 }
 
 func (c *PresetClient) doRequest(req *http.Request, authToken *string) ([]byte, error) {
+	body, err := c.doStream(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+// Sends the request and hands back the response body unread; the caller must close it
+func (c *PresetClient) doStream(req *http.Request, authToken *string) (io.ReadCloser, error) {
 	token := c.Token
 
 	if authToken != nil {
@@ -72,20 +186,21 @@ func (c *PresetClient) doRequest(req *http.Request, authToken *string) ([]byte,
 		req.Header.Set("Authorization", token)
 	}
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.doer().Do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(limitBody(res.Body, c.MaxBodySize))
+		if err != nil && !errors.Is(err, ErrBodyTooLarge) {
+			return nil, err
+		}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+		return nil, &HTTPError{StatusCode: res.StatusCode, Body: body}
 	}
 
-	return body, err
+	return limitBody(res.Body, c.MaxBodySize), nil
 }
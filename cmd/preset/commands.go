@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+	"golang.org/x/term"
+)
+
+// Team roles as accepted by --role
+var teamRoles = map[string]preset.TeamRoleEnum{
+	"admin": preset.TEAM_ADMIN,
+	"user":  preset.TEAM_USER,
+}
+
+// Logs in with an API key. The secret is never taken as a flag, where it would end up in shell
+// history and process listings; it is read from PRESET_API_SECRET, prompted for without echo
+// when stdin is a terminal, or read from the first line of stdin otherwise.
+func (a *app) authLogin(args []string) error {
+	fs := a.flagSet("auth login")
+	name := fs.String("name", "", "API token name")
+	apiURL := fs.String("api-url", "", "API URL, if not the default "+preset.APIURL)
+	team := fs.Int("team", 0, "team used when commands aren't given --team")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		fmt.Fprintln(a.stderr, "auth login needs --name")
+		return errUsage
+	}
+
+	secret, err := a.readSecret()
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		fmt.Fprintln(a.stderr, "auth login needs the API token secret in PRESET_API_SECRET or on stdin")
+		return errUsage
+	}
+
+	profile := Profile{APIURL: *apiURL, TokenName: *name, Secret: secret, Team: *team}
+	var host *string
+	if *apiURL != "" {
+		host = apiURL
+	}
+	_, err = preset.NewClient(host, name, &secret)
+	if err != nil {
+		return fmt.Errorf("checking API key: %w", err)
+	}
+
+	config, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+	profileName := a.profile
+	if profileName == "" {
+		profileName = "default"
+	}
+	config.Profiles[profileName] = profile
+	if config.DefaultProfile == "" {
+		config.DefaultProfile = profileName
+	}
+	err = saveConfig(a.configPath, config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Saved profile %q to %s\n", profileName, a.configPath)
+	return nil
+}
+
+// Returns the API token secret from PRESET_API_SECRET, a prompt or stdin
+func (a *app) readSecret() (string, error) {
+	if secret := os.Getenv("PRESET_API_SECRET"); secret != "" {
+		return secret, nil
+	}
+
+	if f, ok := a.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(a.stderr, "API token secret: ")
+		secret, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(a.stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading secret: %w", err)
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("reading secret: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (a *app) teamsList(args []string) error {
+	fs := a.flagSet("teams list")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+
+	teams, err := s.client.GetAllTeams(s.token)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, team := range *teams {
+		rows = append(rows, []string{
+			strconv.Itoa(team.ID), team.Name, team.Title, string(team.Tier),
+			strconv.Itoa(team.UserCount), strconv.Itoa(team.WorkspaceCount),
+		})
+	}
+	return render(a.stdout, a.output, teams, []string{"ID", "NAME", "TITLE", "TIER", "USERS", "WORKSPACES"}, rows)
+}
+
+func workspaceRows(workspaces []preset.Workspace) [][]string {
+	rows := [][]string{}
+	for _, workspace := range workspaces {
+		rows = append(rows, []string{
+			strconv.Itoa(workspace.ID), workspace.Name, workspace.Title, workspace.Region,
+			string(workspace.WorkspaceStatus), workspace.Hostname,
+		})
+	}
+	return rows
+}
+
+var workspaceHeader = []string{"ID", "NAME", "TITLE", "REGION", "STATUS", "HOSTNAME"}
+
+func (a *app) workspacesList(args []string) error {
+	fs := a.flagSet("workspaces list")
+	teamFlag := fs.Int("team", 0, "team ID")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+	teamID, err := s.team(*teamFlag)
+	if err != nil {
+		return err
+	}
+
+	workspaces, err := s.client.GetAllWorkspaces(teamID, s.token)
+	if err != nil {
+		return err
+	}
+	return render(a.stdout, a.output, workspaces, workspaceHeader, workspaceRows(*workspaces))
+}
+
+func (a *app) workspacesCreate(args []string) error {
+	fs := a.flagSet("workspaces create")
+	teamFlag := fs.Int("team", 0, "team ID")
+	title := fs.String("title", "", "workspace title")
+	region := fs.String("region", "", "region to create the workspace in")
+	descr := fs.String("description", "", "workspace description")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *title == "" {
+		fmt.Fprintln(a.stderr, "workspaces create needs --title")
+		return errUsage
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+	teamID, err := s.team(*teamFlag)
+	if err != nil {
+		return err
+	}
+
+	if a.skipForDryRun("create workspace %q in team %d", *title, teamID) {
+		return nil
+	}
+	workspace, err := s.client.CreateWorkspace(teamID, preset.WorkspacePayload{Title: *title, Region: *region, Descr: *descr}, s.token)
+	if err != nil {
+		return err
+	}
+	return render(a.stdout, a.output, workspace, workspaceHeader, workspaceRows([]preset.Workspace{*workspace}))
+}
+
+func (a *app) workspacesDelete(args []string) error {
+	fs := a.flagSet("workspaces delete")
+	teamFlag := fs.Int("team", 0, "team ID")
+	workspaceID := fs.Int("workspace", 0, "workspace ID")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *workspaceID == 0 {
+		fmt.Fprintln(a.stderr, "workspaces delete needs --workspace")
+		return errUsage
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+	teamID, err := s.team(*teamFlag)
+	if err != nil {
+		return err
+	}
+
+	if a.skipForDryRun("delete workspace %d of team %d", *workspaceID, teamID) {
+		return nil
+	}
+	err = s.client.DeleteWorkspace(teamID, *workspaceID, s.token)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Deleted workspace %d\n", *workspaceID)
+	return nil
+}
+
+func userName(user preset.User) string {
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
+
+func (a *app) membersList(args []string) error {
+	fs := a.flagSet("members list")
+	teamFlag := fs.Int("team", 0, "team ID")
+	workspaceID := fs.Int("workspace", 0, "workspace ID, to list its members rather than the team's")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+	teamID, err := s.team(*teamFlag)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	if *workspaceID != 0 {
+		memberships, err := s.client.GetWorkspaceMembership(teamID, *workspaceID, s.token)
+		if err != nil {
+			return err
+		}
+		for _, membership := range *memberships {
+			rows = append(rows, []string{
+				strconv.Itoa(membership.User.ID), membership.User.Email, userName(membership.User),
+				membership.WorkspaceRole.Name,
+			})
+		}
+		return render(a.stdout, a.output, memberships, []string{"USER_ID", "EMAIL", "NAME", "WORKSPACE_ROLE"}, rows)
+	}
+
+	memberships, err := s.client.GetTeamMembership(teamID, 0, s.token)
+	if err != nil {
+		return err
+	}
+	for _, membership := range *memberships {
+		rows = append(rows, []string{
+			strconv.Itoa(membership.User.ID), membership.User.Email, userName(membership.User),
+			membership.TeamRole.Name,
+		})
+	}
+	return render(a.stdout, a.output, memberships, []string{"USER_ID", "EMAIL", "NAME", "TEAM_ROLE"}, rows)
+}
+
+func (a *app) membersSetRole(args []string) error {
+	fs := a.flagSet("members set-role")
+	teamFlag := fs.Int("team", 0, "team ID")
+	workspaceID := fs.Int("workspace", 0, "workspace ID, to set a workspace role rather than a team role")
+	userID := fs.Int("user", 0, "user ID")
+	role := fs.String("role", "", "team role (admin, user) or workspace role (e.g. viewer, primary contributor)")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *userID == 0 || *role == "" {
+		fmt.Fprintln(a.stderr, "members set-role needs --user and --role")
+		return errUsage
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+	teamID, err := s.team(*teamFlag)
+	if err != nil {
+		return err
+	}
+
+	if *workspaceID != 0 {
+		if a.skipForDryRun("give user %d the %q role in workspace %d", *userID, *role, *workspaceID) {
+			return nil
+		}
+		membership, err := s.client.UpdateUserWorkspaceRole(teamID, *workspaceID, *userID, strings.ToLower(*role), s.token)
+		if err != nil {
+			return err
+		}
+		return render(a.stdout, a.output, membership, []string{"USER_ID", "EMAIL", "NAME", "WORKSPACE_ROLE"}, [][]string{{
+			strconv.Itoa(membership.User.ID), membership.User.Email, userName(membership.User), membership.WorkspaceRole.Name,
+		}})
+	}
+
+	roleID, ok := teamRoles[strings.ToLower(*role)]
+	if !ok {
+		return fmt.Errorf("invalid team role %q; use admin or user", *role)
+	}
+	if a.skipForDryRun("give user %d the %q role in team %d", *userID, *role, teamID) {
+		return nil
+	}
+	membership, err := s.client.UpdateUserTeamRole(teamID, *userID, roleID, s.token)
+	if err != nil {
+		return err
+	}
+	return render(a.stdout, a.output, membership, []string{"USER_ID", "EMAIL", "NAME", "TEAM_ROLE"}, [][]string{{
+		strconv.Itoa(membership.User.ID), membership.User.Email, userName(membership.User), membership.TeamRole.Name,
+	}})
+}
+
+func (a *app) membersRemove(args []string) error {
+	fs := a.flagSet("members remove")
+	teamFlag := fs.Int("team", 0, "team ID")
+	userID := fs.Int("user", 0, "user ID")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *userID == 0 {
+		fmt.Fprintln(a.stderr, "members remove needs --user")
+		return errUsage
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+	teamID, err := s.team(*teamFlag)
+	if err != nil {
+		return err
+	}
+
+	if a.skipForDryRun("remove user %d from team %d", *userID, teamID) {
+		return nil
+	}
+	err = s.client.DeleteTeamMembership(teamID, *userID, s.token)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Removed user %d from team %d\n", *userID, teamID)
+	return nil
+}
+
+func (a *app) invitesSend(args []string) error {
+	fs := a.flagSet("invites send")
+	teamFlag := fs.Int("team", 0, "team ID")
+	emails := fs.String("email", "", "comma-separated email addresses to invite")
+	role := fs.String("role", "user", "team role: admin or user")
+	workspaceID := fs.Int("workspace", 0, "workspace to add invitees to once they accept")
+	workspaceRole := fs.String("workspace-role", "", "workspace role identifier for --workspace, e.g. PresetAlpha")
+	err := a.parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *emails == "" {
+		fmt.Fprintln(a.stderr, "invites send needs --email")
+		return errUsage
+	}
+	roleID, ok := teamRoles[strings.ToLower(*role)]
+	if !ok {
+		return fmt.Errorf("invalid team role %q; use admin or user", *role)
+	}
+	s, err := a.connect()
+	if err != nil {
+		return err
+	}
+	teamID, err := s.team(*teamFlag)
+	if err != nil {
+		return err
+	}
+
+	invites := []preset.Invite{}
+	for _, email := range strings.Split(*emails, ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			invites = append(invites, preset.Invite{Email: email, TeamRoleID: roleID, WorkspaceID: *workspaceID, WorkspaceRole: *workspaceRole})
+		}
+	}
+
+	if a.skipForDryRun("invite %d people to team %d", len(invites), teamID) {
+		return nil
+	}
+	sent, err := s.client.SendInvites(teamID, invites, s.token)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, invite := range *sent {
+		rows = append(rows, []string{strconv.Itoa(invite.ID), invite.Email, strconv.Itoa(int(invite.TeamRoleID)), invite.ExpirationDate.Time.Format("2006-01-02")})
+	}
+	return render(a.stdout, a.output, sent, []string{"ID", "EMAIL", "TEAM_ROLE_ID", "EXPIRES"}, rows)
+}
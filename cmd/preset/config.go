@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile holds the credentials and defaults for one Preset account
+type Profile struct {
+	APIURL    string `yaml:"api_url,omitempty"`
+	TokenName string `yaml:"token_name"`
+	Secret    string `yaml:"secret"`
+	// Team used when --team isn't given
+	Team int `yaml:"team,omitempty"`
+}
+
+// Config is the CLI configuration file, holding named profiles
+type Config struct {
+	DefaultProfile string             `yaml:"default_profile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+// Returns the config file path: PRESET_CONFIG if set, otherwise preset/config.yaml in the user
+// config directory
+func defaultConfigPath() string {
+	if path := os.Getenv("PRESET_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "preset.yaml"
+	}
+	return filepath.Join(dir, "preset", "config.yaml")
+}
+
+// Reads the config file at path; a missing file is an empty config
+func loadConfig(path string) (*Config, error) {
+	config := &Config{Profiles: map[string]Profile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+	return config, nil
+}
+
+// Writes the config to path, readable only by the user since it holds secrets
+func saveConfig(path string, config *Config) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Returns the named profile, or the default one if name is empty. PRESET_API_TOKEN and
+// PRESET_API_SECRET override the stored credentials.
+func (c *Config) profile(name string) (string, Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		name = "default"
+	}
+
+	profile, ok := c.Profiles[name]
+	if token := os.Getenv("PRESET_API_TOKEN"); token != "" {
+		profile.TokenName = token
+		profile.Secret = os.Getenv("PRESET_API_SECRET")
+		ok = true
+	}
+	if !ok {
+		return name, profile, fmt.Errorf("no profile %q; run preset auth login first", name)
+	}
+	return name, profile, nil
+}
@@ -0,0 +1,229 @@
+// Command preset manages Preset teams, workspaces and members from the command line.
+//
+//	preset [global flags] <command> <subcommand> [flags]
+//
+// Commands:
+//
+//	auth login          store an API key in a profile
+//	teams list          list the teams the API key can access
+//	workspaces list     list a team's workspaces
+//	workspaces create   create a workspace
+//	workspaces delete   delete a workspace
+//	members list        list the members of a team or workspace
+//	members set-role    change a member's team or workspace role
+//	members remove      remove a member from a team
+//	invites send        invite people to a team
+//
+// Global flags may also follow the subcommand:
+//
+//	--config   config file, defaulting to $PRESET_CONFIG or preset/config.yaml in the user config dir
+//	--profile  profile to use, defaulting to the config's default_profile
+//	--output   table, json or csv
+//	--dry-run  print what mutations would do without doing them
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+// Returned for bad invocations, which exit with status 2
+var errUsage = errors.New("usage")
+
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	configPath string
+	profile    string
+	output     string
+	dryRun     bool
+}
+
+type command struct {
+	summary string
+	run     func(a *app, args []string) error
+}
+
+var commands = map[string]map[string]command{
+	"auth": {
+		"login": {"store an API key in a profile", (*app).authLogin},
+	},
+	"teams": {
+		"list": {"list the teams the API key can access", (*app).teamsList},
+	},
+	"workspaces": {
+		"list":   {"list a team's workspaces", (*app).workspacesList},
+		"create": {"create a workspace", (*app).workspacesCreate},
+		"delete": {"delete a workspace", (*app).workspacesDelete},
+	},
+	"members": {
+		"list":     {"list the members of a team or workspace", (*app).membersList},
+		"set-role": {"change a member's team or workspace role", (*app).membersSetRole},
+		"remove":   {"remove a member from a team", (*app).membersRemove},
+	},
+	"invites": {
+		"send": {"invite people to a team", (*app).invitesSend},
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Runs the CLI with args, returning the exit status
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	a := &app{stdin: stdin, stdout: stdout, stderr: stderr, configPath: defaultConfigPath(), output: OUTPUT_TABLE}
+
+	err := a.dispatch(args)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	}
+	fmt.Fprintf(stderr, "preset: %v\n", err)
+	return 1
+}
+
+func (a *app) dispatch(args []string) error {
+	root := a.flagSet("preset")
+	root.Usage = func() { a.usage() }
+	err := root.Parse(args)
+	if err != nil {
+		return err
+	}
+	args = root.Args()
+
+	// Checked before running the command, so a bad format can't fail it after a mutation
+	err = checkOutput(a.output)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		a.usage()
+		return errUsage
+	}
+
+	group, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "preset: unknown command %q\n", args[0])
+		a.usage()
+		return errUsage
+	}
+	cmd, ok := group[args[1]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "preset: unknown subcommand %q for %s\n", args[1], args[0])
+		a.usage()
+		return errUsage
+	}
+
+	return cmd.run(a, args[2:])
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "usage: preset [--config file] [--profile name] [--output table|json|csv] [--dry-run] <command> <subcommand> [flags]")
+	fmt.Fprintln(a.stderr, "\ncommands:")
+
+	names := []string{}
+	for group, subcommands := range commands {
+		for name := range subcommands {
+			names = append(names, group+" "+name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts := strings.SplitN(name, " ", 2)
+		fmt.Fprintf(a.stderr, "  %-20s %s\n", name, commands[parts[0]][parts[1]].summary)
+	}
+}
+
+// Returns a flag set holding the global flags, so they may appear before or after subcommands
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.configPath, "config", a.configPath, "config file")
+	fs.StringVar(&a.profile, "profile", a.profile, "profile to use")
+	fs.StringVar(&a.output, "output", a.output, "output format: table, json or csv")
+	fs.BoolVar(&a.dryRun, "dry-run", a.dryRun, "print what mutations would do without doing them")
+	return fs
+}
+
+// Parses a subcommand's flags, rejecting stray arguments and, since global flags may follow the
+// subcommand, checking --output again before the command connects
+func (a *app) parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return errUsage
+	}
+	return checkOutput(a.output)
+}
+
+// Session is an authenticated client along with the profile it was made from
+type session struct {
+	client  *preset.PresetClient
+	token   *string
+	profile Profile
+}
+
+// Returns a client authenticated with the selected profile
+func (a *app) connect() (*session, error) {
+	config, err := loadConfig(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+	_, profile, err := config.profile(a.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var host *string
+	if profile.APIURL != "" {
+		host = &profile.APIURL
+	}
+	client, err := preset.NewClient(host, &profile.TokenName, &profile.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("authenticating: %w", err)
+	}
+
+	token := "Bearer " + client.Token
+	return &session{client: client, token: &token, profile: profile}, nil
+}
+
+// Returns the team from --team, falling back to the profile's
+func (s *session) team(flagValue int) (int, error) {
+	if flagValue != 0 {
+		return flagValue, nil
+	}
+	if s.profile.Team != 0 {
+		return s.profile.Team, nil
+	}
+	return 0, fmt.Errorf("no team given; pass --team or set team in the profile")
+}
+
+// Prints what a mutation would do, reporting whether it should be skipped
+func (a *app) skipForDryRun(format string, args ...interface{}) bool {
+	if !a.dryRun {
+		return false
+	}
+	fmt.Fprintf(a.stdout, "dry run: would "+format+"\n", args...)
+	return true
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+	"github.com/vadivelselvaraj/preset-sdk-go/presettest"
+)
+
+// Runs the CLI, returning its exit status, stdout and stderr
+func runCLI(args ...string) (int, string, string) {
+	return runCLIWithInput("", args...)
+}
+
+// Runs the CLI with stdin reading input, returning its exit status, stdout and stderr
+func runCLIWithInput(input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(input), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+// Starts a fake Preset API and logs a profile into it
+func setup(t *testing.T) (*presettest.Server, preset.Team, string) {
+	t.Setenv("PRESET_API_TOKEN", "")
+	srv := presettest.NewServer(t)
+	srv.RequireAuth = true
+	srv.AddAPIKey("ops", "s3cret")
+	team := srv.AddTeam(preset.Team{Name: "acme", Tier: preset.TIER_ENTERPRISE})
+
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	status, stdout, stderr := runCLIWithInput("s3cret\n", "--config", config, "auth", "login", "--name", "ops", "--api-url", srv.URL)
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, `Saved profile "default"`)
+
+	return srv, team, config
+}
+
+func TestAuthLogin_RejectsBadKey(t *testing.T) {
+	srv := presettest.NewServer(t)
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	status, _, stderr := runCLIWithInput("wrong\n", "--config", config, "auth", "login", "--name", "ops", "--api-url", srv.URL)
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "checking API key")
+
+	loaded, err := loadConfig(config)
+	assert.NoError(t, err)
+	assert.Empty(t, loaded.Profiles)
+}
+
+func TestAuthLogin_SecretSources(t *testing.T) {
+	t.Setenv("PRESET_API_TOKEN", "")
+	srv := presettest.NewServer(t)
+	srv.RequireAuth = true
+	srv.AddAPIKey("ops", "s3cret")
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	// The secret is no longer accepted as a flag
+	status, _, _ := runCLI("--config", config, "auth", "login", "--name", "ops", "--secret", "s3cret", "--api-url", srv.URL)
+	assert.Equal(t, 2, status)
+
+	t.Setenv("PRESET_API_SECRET", "")
+	status, _, stderr := runCLI("--config", config, "auth", "login", "--name", "ops", "--api-url", srv.URL)
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "PRESET_API_SECRET")
+
+	t.Setenv("PRESET_API_SECRET", "s3cret")
+	status, _, stderr = runCLI("--config", config, "auth", "login", "--name", "ops", "--api-url", srv.URL)
+	assert.Equal(t, 0, status, stderr)
+
+	loaded, err := loadConfig(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", loaded.Profiles["default"].Secret)
+}
+
+func TestTeamsList_Formats(t *testing.T) {
+	_, team, config := setup(t)
+
+	status, stdout, stderr := runCLI("--config", config, "teams", "list")
+	assert.Equal(t, 0, status, stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, []string{"ID", "NAME", "TITLE", "TIER", "USERS", "WORKSPACES"}, strings.Fields(lines[0]))
+	assert.Contains(t, lines[1], "ENTERPRISE")
+
+	status, stdout, _ = runCLI("teams", "list", "--config", config, "--output", "json")
+	assert.Equal(t, 0, status)
+	teams := []preset.Team{}
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &teams))
+	assert.Equal(t, team.ID, teams[0].ID)
+
+	status, stdout, _ = runCLI("--config", config, "--output", "csv", "teams", "list")
+	assert.Equal(t, 0, status)
+	assert.True(t, strings.HasPrefix(stdout, "ID,NAME,TITLE,TIER,USERS,WORKSPACES\n"))
+
+	status, _, stderr = runCLI("--config", config, "--output", "xml", "teams", "list")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "unknown output format")
+}
+
+func TestWorkspaces_CreateDeleteAndDryRun(t *testing.T) {
+	srv, team, config := setup(t)
+	teamFlag := []string{"--config", config, "--team", strconv.Itoa(team.ID)}
+
+	// A bad output format fails before anything is created, wherever the flag is given
+	srv.ResetCalls()
+	status, _, stderr := runCLI(append([]string{"workspaces", "create", "--title", "Analytics", "--output", "yaml"}, teamFlag...)...)
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "unknown output format")
+	status, _, _ = runCLI(append([]string{"--output", "yaml", "workspaces", "create", "--title", "Analytics"}, teamFlag...)...)
+	assert.Equal(t, 1, status)
+	assert.Empty(t, srv.Workspaces(team.ID))
+	assert.Empty(t, srv.Calls())
+
+	status, stdout, stderr := runCLI(append([]string{"workspaces", "create", "--title", "Analytics", "--dry-run"}, teamFlag...)...)
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, "dry run: would create workspace \"Analytics\"")
+	assert.Empty(t, srv.Workspaces(team.ID))
+
+	status, stdout, stderr = runCLI(append([]string{"workspaces", "create", "--title", "Analytics"}, teamFlag...)...)
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, "pending")
+	workspaces := srv.Workspaces(team.ID)
+	assert.Len(t, workspaces, 1)
+
+	status, stdout, _ = runCLI(append([]string{"workspaces", "list"}, teamFlag...)...)
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "Analytics")
+
+	status, _, _ = runCLI(append([]string{"workspaces", "delete", "--workspace", strconv.Itoa(workspaces[0].ID)}, teamFlag...)...)
+	assert.Equal(t, 0, status)
+	assert.Empty(t, srv.Workspaces(team.ID))
+}
+
+func TestMembers_SetRoleAndRemove(t *testing.T) {
+	srv, team, config := setup(t)
+	workspace := srv.AddWorkspace(team.ID, preset.Workspace{})
+	member := srv.AddTeamMember(team.ID, preset.User{Email: "ada@example.com", FirstName: "Ada"}, preset.TEAM_USER)
+	flags := []string{"--config", config, "--team", strconv.Itoa(team.ID), "--user", strconv.Itoa(member.User.ID)}
+
+	status, _, stderr := runCLI(append([]string{"members", "set-role", "--role", "admin"}, flags...)...)
+	assert.Equal(t, 0, status, stderr)
+	assert.Equal(t, "Admin", srv.TeamMembers(team.ID)[0].TeamRole.Name)
+
+	status, _, stderr = runCLI(append([]string{"members", "set-role", "--role", "Viewer", "--workspace", strconv.Itoa(workspace.ID)}, flags...)...)
+	assert.Equal(t, 0, status, stderr)
+
+	status, stdout, _ := runCLI("members", "list", "--config", config, "--team", strconv.Itoa(team.ID), "--workspace", strconv.Itoa(workspace.ID), "--output", "csv")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "ada@example.com,Ada,Viewer")
+
+	status, _, _ = runCLI(append([]string{"members", "remove", "--dry-run"}, flags...)...)
+	assert.Equal(t, 0, status)
+	assert.Len(t, srv.TeamMembers(team.ID), 1)
+
+	status, _, _ = runCLI(append([]string{"members", "remove"}, flags...)...)
+	assert.Equal(t, 0, status)
+	assert.Empty(t, srv.TeamMembers(team.ID))
+}
+
+func TestInvitesSend(t *testing.T) {
+	srv, team, config := setup(t)
+
+	status, stdout, stderr := runCLI("invites", "send", "--config", config, "--team", strconv.Itoa(team.ID), "--email", "ada@example.com, bob@example.com", "--role", "admin")
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, "bob@example.com")
+	invites := srv.Invites(team.ID)
+	assert.Len(t, invites, 2)
+	assert.Equal(t, preset.TEAM_ADMIN, invites[0].TeamRoleID)
+}
+
+func TestUsage(t *testing.T) {
+	status, _, stderr := runCLI()
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "workspaces create")
+
+	status, _, _ = runCLI("teams", "explode")
+	assert.Equal(t, 2, status)
+
+	status, _, stderr = runCLI("--config", filepath.Join(t.TempDir(), "none.yaml"), "teams", "list")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "auth login")
+}
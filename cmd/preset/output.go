@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats selected with --output
+const (
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = "json"
+	OUTPUT_CSV   = "csv"
+)
+
+// Writes a result in the given format. JSON output is value itself; table and CSV output are
+// header and rows.
+func render(w io.Writer, format string, value interface{}, header []string, rows [][]string) error {
+	switch format {
+	case OUTPUT_JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case OUTPUT_CSV:
+		writer := csv.NewWriter(w)
+		err := writer.Write(header)
+		if err != nil {
+			return err
+		}
+		err = writer.WriteAll(rows)
+		if err != nil {
+			return err
+		}
+		return writer.Error()
+	case OUTPUT_TABLE, "":
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	}
+	return checkOutput(format)
+}
+
+// Returns an error if render doesn't support format
+func checkOutput(format string) error {
+	switch format {
+	case OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_CSV, "":
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q; use table, json or csv", format)
+}
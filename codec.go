@@ -0,0 +1,98 @@
+package preset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Codec encodes request payloads and decodes response bodies. Set PresetClient.Codec to use a
+// JSON library other than encoding/json; it must honour json.Marshaler and json.Unmarshaler,
+// which several models implement.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	// Returns a decoder reading values straight from r
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder decodes values from a stream, as *json.Decoder does
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// TokenDecoder is a Decoder that can also step through a stream token by token, as
+// *json.Decoder does. SQL Lab results are streamed row by row when the codec's decoders implement
+// it, and decoded in one go otherwise.
+type TokenDecoder interface {
+	Decoder
+	Token() (json.Token, error)
+	More() bool
+}
+
+// JSONCodec is the Codec based on encoding/json, used by default
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// ErrBodyTooLarge is returned when a response body exceeds PresetClient.MaxBodySize
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// Returns the codec the client encodes and decodes with
+func (c *PresetClient) codec() Codec {
+	if c.Codec != nil {
+		return c.Codec
+	}
+	return JSONCodec{}
+}
+
+// Sends the request and decodes the response body into v as it streams in
+func (c *PresetClient) doDecode(req *http.Request, authToken *string, v interface{}) error {
+	body, err := c.doStream(req, authToken)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return c.codec().NewDecoder(body).Decode(v)
+}
+
+// Caps a response body at a number of bytes, failing reads past it with ErrBodyTooLarge
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func limitBody(body io.ReadCloser, limit int64) io.ReadCloser {
+	if limit <= 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, remaining: limit, limit: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Only fail if there is more to read, so a body of exactly the limit is fine
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w: over %d bytes", ErrBodyTooLarge, b.limit)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
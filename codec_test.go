@@ -0,0 +1,150 @@
+package preset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Codec counting its uses, delegating to JSONCodec
+type countingCodec struct {
+	marshals int
+	decoders int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return JSONCodec{}.Marshal(v)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) Decoder {
+	c.decoders++
+	return JSONCodec{}.NewDecoder(r)
+}
+
+func (c *countingCodec) count() int {
+	return c.decoders
+}
+
+func TestCodec_Pluggable(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"payload": {"user": {"id": 7}, "team_role": {"id": 1, "name": "Admin"}}}`))
+	}))
+	defer mockServer.Close()
+
+	codec := &countingCodec{}
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Codec:      codec,
+	}
+
+	membership, err := client.UpdateUserTeamRole(1, 7, TEAM_ADMIN, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Admin", membership.TeamRole.Name)
+	assert.Equal(t, 1, codec.marshals)
+	assert.Equal(t, 1, codec.decoders)
+}
+
+func TestMaxBodySize(t *testing.T) {
+	members := `{"payload": [` + strings.Repeat(`{"user": {"id": 1, "email": "ada@example.com"}},`, 999) + `{"user": {"id": 2}}]}`
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/teams" {
+			// Simulate a 500 internal server error with a long body
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(strings.Repeat("x", 1000)))
+			return
+		}
+		w.Write([]byte(members))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:     mockServer.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		Token:       "mockAccessToken",
+		MaxBodySize: int64(len(members)),
+	}
+
+	memberships, err := client.GetTeamMembership(1, 0, nil)
+	assert.NoError(t, err)
+	assert.Len(t, *memberships, 1000)
+
+	client.MaxBodySize = int64(len(members)) - 1
+	_, err = client.GetTeamMembership(1, 0, nil)
+	assert.True(t, errors.Is(err, ErrBodyTooLarge))
+
+	// Error bodies are cut at the limit
+	client.MaxBodySize = 10
+	_, err = client.GetAllTeams(nil)
+	assert.Error(t, err)
+	assert.Equal(t, "status: 500, body: xxxxxxxxxx", err.Error())
+}
+
+// Codec whose decoders can only decode whole values, so results can't be streamed
+type plainCodec struct {
+	countingCodec
+}
+
+func (c *plainCodec) NewDecoder(r io.Reader) Decoder {
+	c.decoders++
+	return struct{ Decoder }{JSONCodec{}.NewDecoder(r)}
+}
+
+func TestCodec_UsedForQueryResultsAndDeletes(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.Write([]byte(`{"payload": {}}`))
+			return
+		}
+		w.Write([]byte(`{"status": "failed", "query_id": 42, "error": "boom", "data": [{"n": 1}, {"n": 2}], "columns": [{"name": "n"}]}`))
+	}))
+	defer mockServer.Close()
+
+	for name, codec := range map[string]interface {
+		Codec
+		count() int
+	}{
+		"streaming": &countingCodec{},
+		"buffered":  &plainCodec{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := &PresetClient{
+				BaseURL:    mockServer.URL,
+				HTTPClient: &http.Client{Timeout: 10 * time.Second},
+				Token:      "mockAccessToken",
+				Codec:      codec,
+			}
+			superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+			rows, err := superset.ExecuteSQL(context.Background(), 1, "public", "SELECT n", SQLOptions{})
+			assert.NoError(t, err)
+			defer rows.Close()
+
+			// Numbers are only kept as json.Number by decoders that support UseNumber
+			numbers := []string{}
+			for rows.Next() {
+				numbers = append(numbers, fmt.Sprint(rows.Row()["n"]))
+			}
+			assert.Equal(t, []string{"1", "2"}, numbers)
+			assert.Error(t, rows.Err())
+			assert.Equal(t, 42, rows.QueryID())
+			assert.Len(t, rows.Columns(), 1)
+
+			assert.NoError(t, client.DeleteTeamMembership(1, 7, nil))
+			assert.Equal(t, 2, codec.count())
+		})
+	}
+}
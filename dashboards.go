@@ -0,0 +1,164 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Returns all dashboards of the workspace
+func (s *SupersetClient) GetAllDashboards(ctx context.Context) (*[]Dashboard, error) {
+	dashboards, err := getAllPages[Dashboard](ctx, s, "GetAllDashboards", "/api/v1/dashboard/")
+	if err != nil {
+		return nil, err
+	}
+
+	return &dashboards, nil
+}
+
+// Returns a single dashboard, including its layout and metadata
+func (s *SupersetClient) GetDashboard(ctx context.Context, dashboardID int) (*Dashboard, error) {
+	req, err := s.newRequest(ctx, "GetDashboard", "GET", fmt.Sprintf("/api/v1/dashboard/%d", dashboardID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dr := DashboardResponse{}
+	err = s.doDecode(req, &dr)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := dr.Result
+	return &dashboard, nil
+}
+
+// Creates a dashboard and returns its ID
+func (s *SupersetClient) CreateDashboard(ctx context.Context, payload DashboardPayload) (int, error) {
+	req, err := s.newRequest(ctx, "CreateDashboard", "POST", "/api/v1/dashboard/", payload)
+	if err != nil {
+		return 0, err
+	}
+
+	dmr := DashboardMutationResponse{}
+	err = s.doDecode(req, &dmr)
+	if err != nil {
+		return 0, err
+	}
+
+	return dmr.ID, nil
+}
+
+// Updates the fields set in payload on an existing dashboard
+func (s *SupersetClient) UpdateDashboard(ctx context.Context, dashboardID int, payload DashboardPayload) error {
+	req, err := s.newRequest(ctx, "UpdateDashboard", "PUT", fmt.Sprintf("/api/v1/dashboard/%d", dashboardID), payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Deletes a dashboard
+func (s *SupersetClient) DeleteDashboard(ctx context.Context, dashboardID int) error {
+	req, err := s.newRequest(ctx, "DeleteDashboard", "DELETE", fmt.Sprintf("/api/v1/dashboard/%d", dashboardID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Replaces the native filter configuration of a dashboard, leaving the rest of its metadata untouched
+func (s *SupersetClient) UpdateDashboardNativeFilters(ctx context.Context, dashboardID int, filters []NativeFilter) error {
+	dashboard, err := s.GetDashboard(ctx, dashboardID)
+	if err != nil {
+		return err
+	}
+
+	err = dashboard.SetNativeFilters(filters)
+	if err != nil {
+		return err
+	}
+
+	return s.UpdateDashboard(ctx, dashboardID, DashboardPayload{JSONMetadata: &dashboard.JSONMetadata})
+}
+
+// Returns the charts placed on a dashboard
+func (s *SupersetClient) GetDashboardCharts(ctx context.Context, dashboardID int) (*[]DashboardChart, error) {
+	req, err := s.newRequest(ctx, "GetDashboardCharts", "GET", fmt.Sprintf("/api/v1/dashboard/%d/charts", dashboardID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dcr := DashboardChartsResponse{}
+	err = s.doDecode(req, &dcr)
+	if err != nil {
+		return nil, err
+	}
+
+	charts := dcr.Result
+	return &charts, nil
+}
+
+// Returns the datasets that the charts of a dashboard depend on
+func (s *SupersetClient) GetDashboardDatasets(ctx context.Context, dashboardID int) (*[]DashboardDataset, error) {
+	req, err := s.newRequest(ctx, "GetDashboardDatasets", "GET", fmt.Sprintf("/api/v1/dashboard/%d/datasets", dashboardID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ddr := DashboardDatasetsResponse{}
+	err = s.doDecode(req, &ddr)
+	if err != nil {
+		return nil, err
+	}
+
+	datasets := ddr.Result
+	return &datasets, nil
+}
+
+// Decodes the native filter configuration stored in the dashboard's json_metadata
+func (d *Dashboard) NativeFilters() ([]NativeFilter, error) {
+	metadata := DashboardMetadata{}
+	if d.JSONMetadata != "" {
+		err := json.Unmarshal([]byte(d.JSONMetadata), &metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return metadata.NativeFilterConfiguration, nil
+}
+
+// Stores filters as the native filter configuration in the dashboard's json_metadata,
+// keeping any other metadata keys as they are
+func (d *Dashboard) SetNativeFilters(filters []NativeFilter) error {
+	metadata := map[string]json.RawMessage{}
+	if d.JSONMetadata != "" {
+		err := json.Unmarshal([]byte(d.JSONMetadata), &metadata)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filters == nil {
+		filters = []NativeFilter{}
+	}
+
+	filtersBytes, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	metadata["native_filter_configuration"] = filtersBytes
+
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	d.JSONMetadata = string(metadataBytes)
+	return nil
+}
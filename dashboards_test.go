@@ -0,0 +1,294 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSupersetClient(t *testing.T) {
+	client := &PresetClient{
+		BaseURL:    APIURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	superset := client.NewSupersetClient(Workspace{Hostname: "2fa9923f.us2a.app.preset.io"}, nil)
+	assert.Equal(t, "https://2fa9923f.us2a.app.preset.io", superset.BaseURL)
+	assert.Equal(t, client, superset.Preset)
+}
+
+func TestGetAllDashboards_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/dashboard/", r.URL.Path)
+		assert.Equal(t, "(page:0,page_size:100)", r.URL.Query().Get("q"))
+		response := []byte(`{
+			"count": 2,
+			"ids": [1, 2],
+			"result": [
+				{"id": 1, "dashboard_title": "Sales", "slug": "sales", "published": true},
+				{"id": 2, "dashboard_title": "Marketing", "slug": null, "published": false}
+			]
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	dashboards, err := superset.GetAllDashboards(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, dashboards)
+	assert.Len(t, *dashboards, 2)
+	assert.Equal(t, "Sales", (*dashboards)[0].DashboardTitle)
+	assert.Equal(t, false, (*dashboards)[1].Published)
+}
+
+func TestGetDashboard_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/dashboard/7", r.URL.Path)
+		response := []byte(`{
+			"id": 7,
+			"result": {
+				"id": 7,
+				"dashboard_title": "Sales",
+				"json_metadata": "{\"color_scheme\": \"supersetColors\", \"native_filter_configuration\": [{\"id\": \"NATIVE_FILTER-1\", \"name\": \"Region\", \"filterType\": \"filter_select\", \"type\": \"NATIVE_FILTER\", \"targets\": [{\"datasetId\": 12, \"column\": {\"name\": \"region\"}}], \"cascadeParentIds\": [], \"scope\": {\"rootPath\": [\"ROOT_ID\"], \"excluded\": []}}]}",
+				"position_json": "{\"DASHBOARD_VERSION_KEY\": \"v2\"}",
+				"owners": [{"id": 3, "first_name": "John", "last_name": "Doe"}],
+				"roles": [{"id": 4, "name": "Sales"}],
+				"published": true
+			}
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	dashboard, err := superset.GetDashboard(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.NotNil(t, dashboard)
+	assert.Equal(t, 3, dashboard.Owners[0].ID)
+	assert.Equal(t, "Sales", dashboard.Roles[0].Name)
+	assert.Equal(t, `{"DASHBOARD_VERSION_KEY": "v2"}`, dashboard.PositionJSON)
+
+	filters, err := dashboard.NativeFilters()
+	assert.NoError(t, err)
+	assert.Len(t, filters, 1)
+	assert.Equal(t, 12, filters[0].Targets[0].DatasetID)
+	assert.Equal(t, "region", filters[0].Targets[0].Column.Name)
+}
+
+func TestGetDashboard_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	_, err := superset.GetDashboard(context.Background(), 7)
+	assert.Error(t, err)
+}
+
+func TestCreateDashboard_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		json.Unmarshal(body, &payload)
+		assert.Equal(t, map[string]interface{}{"dashboard_title": "Sales", "owners": []interface{}{float64(3)}}, payload)
+
+		// Simulate a 201 created response
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 9, "result": {"dashboard_title": "Sales", "owners": [3]}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	title := "Sales"
+	owners := []int{3}
+	id, err := superset.CreateDashboard(context.Background(), DashboardPayload{DashboardTitle: &title, Owners: &owners})
+	assert.NoError(t, err)
+	assert.Equal(t, 9, id)
+}
+
+func TestDeleteDashboard_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	err := superset.DeleteDashboard(context.Background(), 7)
+	assert.Error(t, err)
+}
+
+func TestUpdateDashboardNativeFilters_KeepsOtherMetadata(t *testing.T) {
+	var updated map[string]interface{}
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 7, "result": {"id": 7, "json_metadata": "{\"color_scheme\": \"supersetColors\", \"native_filter_configuration\": []}"}}`))
+			return
+		}
+
+		assert.Equal(t, "PUT", r.Method)
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &updated)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": 7, "result": {}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	filters := []NativeFilter{{ID: "NATIVE_FILTER-1", Name: "Region", FilterType: "filter_select", Type: "NATIVE_FILTER"}}
+	err := superset.UpdateDashboardNativeFilters(context.Background(), 7, filters)
+	assert.NoError(t, err)
+	assert.Len(t, updated, 1)
+
+	metadata := map[string]interface{}{}
+	err = json.Unmarshal([]byte(updated["json_metadata"].(string)), &metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, "supersetColors", metadata["color_scheme"])
+	assert.Len(t, metadata["native_filter_configuration"], 1)
+}
+
+func TestNativeFilters_KeepUnmodeledSettings(t *testing.T) {
+	dashboard := Dashboard{JSONMetadata: `{"native_filter_configuration": [{"id": "NATIVE_FILTER-1", "name": "Region", "requiredFirst": true, "sortMetric": "count", "adhoc_filters": [{"clause": "WHERE"}], "targets": [], "cascadeParentIds": [], "scope": {"rootPath": ["ROOT_ID"], "excluded": []}}]}`}
+
+	filters, err := dashboard.NativeFilters()
+	assert.NoError(t, err)
+	assert.Len(t, filters, 1)
+	assert.JSONEq(t, `"count"`, string(filters[0].Extra["sortMetric"]))
+
+	filters[0].Name = "Sales region"
+	err = dashboard.SetNativeFilters(filters)
+	assert.NoError(t, err)
+
+	metadata := map[string][]map[string]interface{}{}
+	err = json.Unmarshal([]byte(dashboard.JSONMetadata), &metadata)
+	assert.NoError(t, err)
+	filter := metadata["native_filter_configuration"][0]
+	assert.Equal(t, "Sales region", filter["name"])
+	assert.Equal(t, true, filter["requiredFirst"])
+	assert.Equal(t, "count", filter["sortMetric"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"clause": "WHERE"}}, filter["adhoc_filters"])
+}
+
+func TestGetDashboardDatasets_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/dashboard/7/datasets", r.URL.Path)
+		response := []byte(`{
+			"result": [
+				{
+					"id": 12,
+					"table_name": "orders",
+					"schema": "public",
+					"database": {"id": 1, "database_name": "warehouse", "backend": "postgresql"}
+				}
+			]
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	datasets, err := superset.GetDashboardDatasets(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, *datasets, 1)
+	assert.Equal(t, "orders", (*datasets)[0].TableName)
+	assert.Equal(t, "warehouse", (*datasets)[0].Database.DatabaseName)
+}
+
+func TestGetDashboardCharts_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/dashboard/7/charts", r.URL.Path)
+		response := []byte(`{
+			"result": [
+				{"id": 21, "slice_name": "Revenue", "viz_type": "big_number", "form_data": {"datasource": "12__table"}}
+			]
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	charts, err := superset.GetDashboardCharts(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, *charts, 1)
+	assert.Equal(t, "Revenue", (*charts)[0].SliceName)
+	assert.Equal(t, "12__table", (*charts)[0].FormData["datasource"])
+}
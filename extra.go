@@ -0,0 +1,276 @@
+package preset
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExtraFields holds the fields of a payload that the SDK doesn't model yet, keyed by JSON name.
+// Models with an Extra field keep them when decoded and write them back when encoded.
+type ExtraFields map[string]json.RawMessage
+
+// Decodes the field stored under name into v, reporting whether it was present
+func (e ExtraFields) Get(name string, v interface{}) (bool, error) {
+	value, found := e[name]
+	if !found {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(value, v)
+}
+
+// SchemaDriftError lists the fields of a payload that the SDK's models don't know about
+type SchemaDriftError struct {
+	Fields []string
+}
+
+func (e *SchemaDriftError) Error() string {
+	return fmt.Sprintf("unknown fields in payload: %s", strings.Join(e.Fields, ", "))
+}
+
+// Decodes data into v like json.Unmarshal, but fails with a SchemaDriftError if the payload has
+// fields that the types in v don't model, at any depth. Meant for tests that check recorded
+// fixtures against the models, so that API changes show up as test failures instead of silently
+// landing in Extra or being dropped.
+func DecodeStrict(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if err != nil {
+		return err
+	}
+
+	var payload interface{}
+	err = json.Unmarshal(data, &payload)
+	if err != nil {
+		return err
+	}
+
+	fields := []string{}
+	collectUnknownFields(reflect.TypeOf(v), payload, "", &fields)
+	if len(fields) > 0 {
+		sort.Strings(fields)
+		return &SchemaDriftError{Fields: fields}
+	}
+
+	return nil
+}
+
+// The models below keep unknown fields in Extra. Each decodes through a method-less copy of
+// itself, so that encoding/json doesn't call back into the method.
+
+func (t *Team) UnmarshalJSON(data []byte) error {
+	type team Team
+	return unmarshalWithExtra(data, (*team)(t), &t.Extra)
+}
+
+func (t Team) MarshalJSON() ([]byte, error) {
+	type team Team
+	return marshalWithExtra(team(t), t.Extra)
+}
+
+func (w *Workspace) UnmarshalJSON(data []byte) error {
+	type workspace Workspace
+	return unmarshalWithExtra(data, (*workspace)(w), &w.Extra)
+}
+
+func (w Workspace) MarshalJSON() ([]byte, error) {
+	type workspace Workspace
+	return marshalWithExtra(workspace(w), w.Extra)
+}
+
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	return unmarshalWithExtra(data, (*user)(u), &u.Extra)
+}
+
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	return marshalWithExtra(user(u), u.Extra)
+}
+
+func (m *TeamMembership) UnmarshalJSON(data []byte) error {
+	type teamMembership TeamMembership
+	return unmarshalWithExtra(data, (*teamMembership)(m), &m.Extra)
+}
+
+func (m TeamMembership) MarshalJSON() ([]byte, error) {
+	type teamMembership TeamMembership
+	return marshalWithExtra(teamMembership(m), m.Extra)
+}
+
+func (m *WorkspaceMembership) UnmarshalJSON(data []byte) error {
+	type workspaceMembership WorkspaceMembership
+	return unmarshalWithExtra(data, (*workspaceMembership)(m), &m.Extra)
+}
+
+func (m WorkspaceMembership) MarshalJSON() ([]byte, error) {
+	type workspaceMembership WorkspaceMembership
+	return marshalWithExtra(workspaceMembership(m), m.Extra)
+}
+
+func (f *NativeFilter) UnmarshalJSON(data []byte) error {
+	type nativeFilter NativeFilter
+	return unmarshalWithExtra(data, (*nativeFilter)(f), &f.Extra)
+}
+
+func (f NativeFilter) MarshalJSON() ([]byte, error) {
+	type nativeFilter NativeFilter
+	return marshalWithExtra(nativeFilter(f), f.Extra)
+}
+
+// Decodes data into model, replacing what it held, and stores the top-level fields that model has
+// no field for in extra. model and extra usually belong to the same model.
+func unmarshalWithExtra[T any](data []byte, model *T, extra *ExtraFields) error {
+	decoded := new(T)
+	err := json.Unmarshal(data, decoded)
+	if err != nil {
+		return err
+	}
+
+	all := ExtraFields{}
+	err = json.Unmarshal(data, &all)
+	if err != nil {
+		return err
+	}
+
+	known := jsonFields(reflect.TypeOf(decoded).Elem())
+	for name := range all {
+		if _, found := lookupField(known, name); found {
+			delete(all, name)
+		}
+	}
+
+	*model = *decoded
+	*extra = nil
+	if len(all) > 0 {
+		*extra = all
+	}
+	return nil
+}
+
+// Encodes known and adds the fields of extra it doesn't already have
+func marshalWithExtra[T any](known T, extra ExtraFields) ([]byte, error) {
+	knownBytes, err := json.Marshal(known)
+	if err != nil || len(extra) == 0 {
+		return knownBytes, err
+	}
+
+	all := map[string]json.RawMessage{}
+	err = json.Unmarshal(knownBytes, &all)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range extra {
+		if _, found := all[name]; !found {
+			all[name] = value
+		}
+	}
+
+	return json.Marshal(all)
+}
+
+var fieldCache sync.Map
+
+// Returns the types of the fields of a struct type by JSON name, including the fields of
+// embedded structs that encoding/json flattens into it
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	if fields, found := fieldCache.Load(t); found {
+		return fields.(map[string]reflect.Type)
+	}
+
+	fields := map[string]reflect.Type{}
+	promoted := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embeddedName, embeddedType := range jsonFields(field.Type) {
+				promoted[embeddedName] = embeddedType
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	// Fields declared on the struct itself win over promoted ones
+	for name, fieldType := range promoted {
+		if _, found := fields[name]; !found {
+			fields[name] = fieldType
+		}
+	}
+
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// Returns the type of the field encoding/json decodes the key name into: the field of that exact
+// name, or failing that one whose name matches it case-insensitively
+func lookupField(fields map[string]reflect.Type, name string) (reflect.Type, bool) {
+	if fieldType, found := fields[name]; found {
+		return fieldType, true
+	}
+	for fieldName, fieldType := range fields {
+		if strings.EqualFold(fieldName, name) {
+			return fieldType, true
+		}
+	}
+	return nil, false
+}
+
+// Walks a decoded JSON payload alongside the type it was decoded into and appends the path of
+// every object key that the type has no field for to fields
+func collectUnknownFields(t reflect.Type, payload interface{}, path string, fields *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch value := payload.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			known := jsonFields(t)
+			for name, child := range value {
+				fieldType, found := lookupField(known, name)
+				if !found {
+					*fields = append(*fields, joinPath(path, name))
+					continue
+				}
+				collectUnknownFields(fieldType, child, joinPath(path, name), fields)
+			}
+		case reflect.Map:
+			for name, child := range value {
+				collectUnknownFields(t.Elem(), child, joinPath(path, name), fields)
+			}
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for i, child := range value {
+			collectUnknownFields(t.Elem(), child, fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	}
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
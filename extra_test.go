@@ -0,0 +1,162 @@
+package preset
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceMembership_KeepsUnknownFields(t *testing.T) {
+	data := []byte(`{
+		"is_role_from_group": false,
+		"user": {"id": 123, "email": "user1@example.com", "avatar_url": "https://example.com/a.png"},
+		"workspace_role": {"name": "Viewer", "role_identifier": "PresetReportsOnly"},
+		"granted_on": "2023-08-01T12:00:00Z"
+	}`)
+
+	membership := WorkspaceMembership{}
+	err := json.Unmarshal(data, &membership)
+	assert.NoError(t, err)
+	assert.Equal(t, 123, membership.User.ID)
+	assert.NotContains(t, membership.Extra, "is_role_from_group")
+
+	var grantedOn string
+	found, err := membership.Extra.Get("granted_on", &grantedOn)
+	assert.True(t, found)
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-08-01T12:00:00Z", grantedOn)
+
+	var avatarURL string
+	found, err = membership.User.Extra.Get("avatar_url", &avatarURL)
+	assert.True(t, found)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/a.png", avatarURL)
+
+	found, err = membership.Extra.Get("missing", &avatarURL)
+	assert.False(t, found)
+	assert.NoError(t, err)
+
+	encoded, err := json.Marshal(membership)
+	assert.NoError(t, err)
+	roundTrip := map[string]interface{}{}
+	json.Unmarshal(encoded, &roundTrip)
+	assert.Equal(t, "2023-08-01T12:00:00Z", roundTrip["granted_on"])
+	assert.Equal(t, "https://example.com/a.png", roundTrip["user"].(map[string]interface{})["avatar_url"])
+}
+
+func TestUser_FieldNamesMatchCaseInsensitively(t *testing.T) {
+	data := []byte(`{"ID": 5, "Email": "ada@example.com", "nickname": "ada"}`)
+
+	user := User{}
+	err := json.Unmarshal(data, &user)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, user.ID)
+	assert.Equal(t, ExtraFields{"nickname": json.RawMessage(`"ada"`)}, user.Extra)
+
+	encoded, err := json.Marshal(user)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), `"ID"`)
+	assert.NotContains(t, string(encoded), `"Email"`)
+
+	assert.NoError(t, DecodeStrict([]byte(`{"ID": 5}`), &User{}))
+}
+
+func TestDecodeStrict_MatchingFixture(t *testing.T) {
+	data := []byte(`{
+		"payload": [
+			{
+				"id": 1,
+				"accessible": true,
+				"ai_assist_activated": true,
+				"allow_public_dashboards": false,
+				"changed_on": "2023-08-01T12:00:00Z",
+				"cluster_id": 123,
+				"color": "blue",
+				"created_on": "2023-08-01T10:00:00Z",
+				"deployment_id": 456,
+				"descr": "Test Workspace 1",
+				"hostname": "workspace1.example.com",
+				"icon": "icon1",
+				"maintenance": false,
+				"name": "Workspace1",
+				"region": "us-west",
+				"status": "active",
+				"team_id": 1,
+				"title": "Workspace 1",
+				"workspace_status": "ready"
+			}
+		]
+	}`)
+
+	wgr := WorkspaceGetResponse{}
+	err := DecodeStrict(data, &wgr)
+	assert.NoError(t, err)
+	assert.Equal(t, "Workspace1", wgr.Payload[0].Name)
+}
+
+func TestDecodeStrict_ReportsDrift(t *testing.T) {
+	data := []byte(`{
+		"payload": [
+			{
+				"team_role": {"id": 1, "name": "Admin"},
+				"user": {"id": 1670, "email": "test1@example.com", "mfa_enabled": true},
+				"scim_managed": false
+			}
+		]
+	}`)
+
+	tmr := TeamMembershipResponse{}
+	err := DecodeStrict(data, &tmr)
+
+	drift := &SchemaDriftError{}
+	assert.True(t, errors.As(err, &drift))
+	assert.Equal(t, []string{"payload[0].scim_managed", "payload[0].user.mfa_enabled"}, drift.Fields)
+}
+
+func TestDecodeStrict_ReportsDriftOnEveryModel(t *testing.T) {
+	data := []byte(`{
+		"payload": [
+			{
+				"team_role": {"id": 1, "name": "Admin", "permissions": ["invite"]},
+				"user": {"id": 1670, "email": "test1@example.com"}
+			}
+		],
+		"meta": {"count": 1}
+	}`)
+
+	tmr := TeamMembershipResponse{}
+	err := DecodeStrict(data, &tmr)
+
+	drift := &SchemaDriftError{}
+	assert.True(t, errors.As(err, &drift))
+	assert.Equal(t, []string{"meta", "payload[0].team_role.permissions"}, drift.Fields)
+
+	data = []byte(`{
+		"payload": [
+			{
+				"id": 831,
+				"default_workspace_role": {"name": "Viewer", "role_identifier": "PresetReportsOnly", "rank": 3},
+				"workspace_roles": [{"name": "Admin", "role_identifier": "Admin", "scope": "workspace"}],
+				"feature_flags": {"ai_assist_enabled": true, "embedded_sdk_enabled": true}
+			}
+		]
+	}`)
+
+	tr := TeamResponse{}
+	err = DecodeStrict(data, &tr)
+	assert.True(t, errors.As(err, &drift))
+	assert.Equal(t, []string{
+		"payload[0].default_workspace_role.rank",
+		"payload[0].feature_flags.embedded_sdk_enabled",
+		"payload[0].workspace_roles[0].scope",
+	}, drift.Fields)
+}
+
+func TestDecodeStrict_EmbeddedStructs(t *testing.T) {
+	usage := WorkspaceUsage{}
+	err := DecodeStrict([]byte(`{"workspace_id": 2, "active_users": 4, "query_count": 9}`), &usage)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, usage.ActiveUsers)
+}
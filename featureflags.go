@@ -0,0 +1,163 @@
+package preset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Returns the feature flags of a team, including flags the SDK doesn't model yet
+func (c *PresetClient) GetTeamFeatureFlags(teamID int, authToken *string) (*Flags, error) {
+	return c.getTeamFeatureFlags(context.Background(), teamID, authToken)
+}
+
+func (c *PresetClient) getTeamFeatureFlags(ctx context.Context, teamID int, authToken *string) (*Flags, error) {
+	teams, err := c.getAllTeams(ctx, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, team := range *teams {
+		if team.ID == teamID {
+			flags := team.FeatureFlags
+			return &flags, nil
+		}
+	}
+
+	return nil, fmt.Errorf("team %d not found", teamID)
+}
+
+// Sets team-level feature flags, e.g. workspace_region_select_enabled, and returns the resulting flags.
+// Flags that are not in the map keep their current value.
+func (c *PresetClient) UpdateTeamFeatureFlags(teamID int, flags map[string]interface{}, authToken *string) (*Flags, error) {
+	// Create a map for the request payload
+	payload := map[string]interface{}{
+		"feature_flags": flags,
+	}
+
+	// Convert the payload map to JSON
+	payloadBytes, err := c.codec().Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "UpdateTeamFeatureFlags", teamID, 0), "PATCH", fmt.Sprintf("%s/v1/teams/%d", c.BaseURL, teamID), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	tur := TeamUpdateResponse{}
+	err = c.doDecode(req, authToken, &tur)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := tur.Payload.FeatureFlags
+	return &updated, nil
+}
+
+// Changes workspace-level settings such as AI assist and public dashboards
+func (c *PresetClient) UpdateWorkspaceSettings(teamID int, workspaceID int, settings WorkspaceSettings, authToken *string) (*Workspace, error) {
+	payloadBytes, err := c.codec().Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "UpdateWorkspaceSettings", teamID, workspaceID), "PATCH", fmt.Sprintf("%s/v1/teams/%d/workspaces/%d", c.BaseURL, teamID, workspaceID), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	wur := WorkspaceUpdateResponse{}
+	err = c.doDecode(req, authToken, &wur)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := wur.Payload
+	return &workspace, nil
+}
+
+// Decodes the modeled flags into their fields and keeps every other flag in Other
+func (f *Flags) UnmarshalJSON(data []byte) error {
+	// flags has the fields of Flags but not its methods, which avoids recursing into UnmarshalJSON
+	type flags Flags
+
+	known := flags{}
+	err := json.Unmarshal(data, &known)
+	if err != nil {
+		return err
+	}
+
+	other := map[string]interface{}{}
+	err = json.Unmarshal(data, &other)
+	if err != nil {
+		return err
+	}
+
+	knownBytes, err := json.Marshal(known)
+	if err != nil {
+		return err
+	}
+	knownKeys := map[string]interface{}{}
+	err = json.Unmarshal(knownBytes, &knownKeys)
+	if err != nil {
+		return err
+	}
+	for key := range knownKeys {
+		delete(other, key)
+	}
+
+	*f = Flags(known)
+	if len(other) > 0 {
+		f.Other = other
+	}
+
+	return nil
+}
+
+// Encodes the modeled flags together with the ones kept in Other
+func (f Flags) MarshalJSON() ([]byte, error) {
+	type flags Flags
+
+	knownBytes, err := json.Marshal(flags(f))
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Other) == 0 {
+		return knownBytes, nil
+	}
+
+	all := map[string]interface{}{}
+	err = json.Unmarshal(knownBytes, &all)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range f.Other {
+		// A modeled field always wins over a stale copy in Other
+		if _, found := all[key]; !found {
+			all[key] = value
+		}
+	}
+
+	return json.Marshal(all)
+}
+
+// Reports whether the named flag, modeled or not, is set to true
+func (f Flags) Enabled(name string) bool {
+	flagBytes, err := json.Marshal(f)
+	if err != nil {
+		return false
+	}
+
+	all := map[string]interface{}{}
+	err = json.Unmarshal(flagBytes, &all)
+	if err != nil {
+		return false
+	}
+
+	enabled, _ := all[name].(bool)
+	return enabled
+}
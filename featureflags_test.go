@@ -0,0 +1,129 @@
+package preset
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags_KeepsUnknownFlags(t *testing.T) {
+	data := []byte(`{"alert_reports": true, "ai_assist_enabled": false, "embedded_sdk_enabled": true, "max_rows": 100000}`)
+
+	flags := Flags{}
+	err := json.Unmarshal(data, &flags)
+	assert.NoError(t, err)
+	assert.True(t, flags.AlertReports)
+	assert.Equal(t, map[string]interface{}{"embedded_sdk_enabled": true, "max_rows": float64(100000)}, flags.Other)
+	assert.True(t, flags.Enabled("embedded_sdk_enabled"))
+	assert.True(t, flags.Enabled("alert_reports"))
+	assert.False(t, flags.Enabled("ai_assist_enabled"))
+	assert.False(t, flags.Enabled("missing"))
+
+	roundTrip, err := json.Marshal(flags)
+	assert.NoError(t, err)
+	decoded := map[string]interface{}{}
+	json.Unmarshal(roundTrip, &decoded)
+	assert.Equal(t, true, decoded["embedded_sdk_enabled"])
+	assert.Equal(t, float64(100000), decoded["max_rows"])
+	assert.Equal(t, true, decoded["alert_reports"])
+}
+
+func TestGetTeamFeatureFlags_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": [{"id": 831, "feature_flags": {"audit_log_enabled": true, "embedded_sdk_enabled": true}}]}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	flags, err := client.GetTeamFeatureFlags(831, nil)
+	assert.NoError(t, err)
+	assert.True(t, flags.AuditLogEnabled)
+	assert.True(t, flags.Enabled("embedded_sdk_enabled"))
+
+	_, err = client.GetTeamFeatureFlags(1, nil)
+	assert.EqualError(t, err, "team 1 not found")
+}
+
+func TestUpdateTeamFeatureFlags_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "/v1/teams/831", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"feature_flags": {"workspace_region_select_enabled": false}}`, string(body))
+
+		// Simulate successful response with mock data
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": {"id": 831, "feature_flags": {"workspace_region_select_enabled": false, "alert_reports": true}}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	flags, err := client.UpdateTeamFeatureFlags(831, map[string]interface{}{"workspace_region_select_enabled": false}, nil)
+	assert.NoError(t, err)
+	assert.False(t, flags.WorkspaceRegionSelectEnabled)
+	assert.True(t, flags.AlertReports)
+}
+
+func TestUpdateWorkspaceSettings_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "/v1/teams/1/workspaces/2", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"ai_assist_activated": true}`, string(body))
+
+		// Simulate successful response with mock data
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": {"id": 2, "ai_assist_activated": true, "allow_public_dashboards": false}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	enabled := true
+	workspace, err := client.UpdateWorkspaceSettings(1, 2, WorkspaceSettings{AiAssistActivated: &enabled}, nil)
+	assert.NoError(t, err)
+	assert.True(t, workspace.AiAssistActivated)
+}
+
+func TestUpdateWorkspaceSettings_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	enabled := false
+	_, err := client.UpdateWorkspaceSettings(1, 2, WorkspaceSettings{AllowPublicDashboards: &enabled}, nil)
+	assert.Error(t, err)
+}
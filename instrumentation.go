@@ -0,0 +1,120 @@
+package preset
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Instrumentation observes every API call made by a PresetClient, e.g. to trace it or record
+// metrics. The otelpreset module provides an OpenTelemetry implementation.
+type Instrumentation interface {
+	// Called once per SDK method call, before the cache and retries. The returned context is used
+	// for all of its attempts, and the returned function is called once with the final outcome.
+	StartCall(ctx context.Context, call CallInfo) (context.Context, func(CallResult))
+	// Called before each attempt at sending the request of a call, with the context returned by
+	// StartCall. The returned context is used for the attempt, and the returned function is
+	// called once with its outcome.
+	StartAttempt(ctx context.Context, attempt AttemptInfo) (context.Context, func(CallResult))
+}
+
+// CallInfo describes an API call about to be made
+type CallInfo struct {
+	// Logical operation, named after the SDK method, e.g. preset.GetWorkspaceMembership
+	Operation string
+	Method    string
+	// URL with secrets redacted
+	URL string
+	// IDs of the team and workspace the call concerns, 0 when unknown
+	TeamID      int
+	WorkspaceID int
+}
+
+// AttemptInfo describes an attempt at sending the request of an API call
+type AttemptInfo struct {
+	CallInfo
+	// Attempt at the request, starting at 1
+	Attempt int
+	// Headers of the outgoing request, so trace context can be propagated
+	Header http.Header
+}
+
+// CallResult is the outcome of an API call or of one of its attempts
+type CallResult struct {
+	// HTTP status of the response, 0 if none was received
+	StatusCode int
+	// Transport error, if the request failed before a response was received
+	Err      error
+	Duration time.Duration
+}
+
+// Returns a Middleware reporting every call to instrumentation, including those answered from
+// cache. It must wrap the Cache and Retry middlewares so that attempts are reported inside the call.
+func InstrumentationMiddleware(instrumentation Instrumentation) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx, finish := instrumentation.StartCall(req.Context(), describeCall(req))
+			return observe(next, req.WithContext(ctx), finish)
+		})
+	}
+}
+
+// Returns a Middleware reporting every attempt to instrumentation. It must run inside the Retry
+// middleware so that it sees each attempt.
+func AttemptInstrumentationMiddleware(instrumentation Instrumentation) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			attempt := AttemptInfo{
+				CallInfo: describeCall(req),
+				Attempt:  attemptFrom(req.Context()),
+				Header:   req.Header,
+			}
+			ctx, finish := instrumentation.StartAttempt(req.Context(), attempt)
+			return observe(next, req.WithContext(ctx), finish)
+		})
+	}
+}
+
+// Sends req with next and reports its outcome to finish
+func observe(next Doer, req *http.Request, finish func(CallResult)) (*http.Response, error) {
+	start := time.Now()
+	res, err := next.Do(req)
+	result := CallResult{Err: err, Duration: time.Since(start)}
+	if res != nil {
+		result.StatusCode = res.StatusCode
+	}
+	finish(result)
+
+	return res, err
+}
+
+type operationKey struct{}
+
+type operation struct {
+	name        string
+	teamID      int
+	workspaceID int
+}
+
+// Returns ctx carrying the name of the SDK method that requests made with it belong to, along
+// with the team and workspace they concern, 0 when unknown
+func withOperation(ctx context.Context, name string, teamID int, workspaceID int) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation{name: name, teamID: teamID, workspaceID: workspaceID})
+}
+
+// Describes req for Instrumentation from the operation its context carries
+func describeCall(req *http.Request) CallInfo {
+	call := CallInfo{
+		Operation: "preset.HTTP " + req.Method,
+		Method:    req.Method,
+		URL:       redactURL(req.URL),
+	}
+
+	if op, ok := req.Context().Value(operationKey{}).(operation); ok {
+		call.Operation = "preset." + op.name
+		call.TeamID = op.teamID
+		call.WorkspaceID = op.workspaceID
+	}
+
+	return call
+}
@@ -0,0 +1,192 @@
+package preset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedCall struct {
+	info   CallInfo
+	result CallResult
+}
+
+type recordedAttempt struct {
+	info   AttemptInfo
+	result CallResult
+	// Operation of the call the attempt was started within
+	parent string
+}
+
+type parentCallKey struct{}
+
+// Instrumentation that records every call and attempt it observes
+type recordingInstrumentation struct {
+	mu       sync.Mutex
+	calls    []recordedCall
+	attempts []recordedAttempt
+}
+
+func (r *recordingInstrumentation) StartCall(ctx context.Context, call CallInfo) (context.Context, func(CallResult)) {
+	ctx = context.WithValue(ctx, parentCallKey{}, call.Operation)
+	return ctx, func(result CallResult) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, recordedCall{info: call, result: result})
+	}
+}
+
+func (r *recordingInstrumentation) StartAttempt(ctx context.Context, attempt AttemptInfo) (context.Context, func(CallResult)) {
+	attempt.Header.Set("Traceparent", "00-test")
+	parent, _ := ctx.Value(parentCallKey{}).(string)
+	return ctx, func(result CallResult) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.attempts = append(r.attempts, recordedAttempt{info: attempt, result: result, parent: parent})
+	}
+}
+
+func TestInstrumentation_ManagerOperations(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "00-test", r.Header.Get("Traceparent"))
+		if r.URL.Path == "/v1/teams/12/workspaces/34/memberships" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	instrumentation := &recordingInstrumentation{}
+	client := &PresetClient{
+		BaseURL:         mockServer.URL,
+		HTTPClient:      &http.Client{Timeout: 10 * time.Second},
+		Token:           "mockAccessToken",
+		Instrumentation: instrumentation,
+	}
+
+	_, err := client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	_, err = client.GetWorkspaceMembership(12, 34, nil)
+	assert.Error(t, err)
+
+	assert.Len(t, instrumentation.calls, 2)
+
+	teams := instrumentation.calls[0]
+	assert.Equal(t, "preset.GetAllTeams", teams.info.Operation)
+	assert.Equal(t, "GET", teams.info.Method)
+	assert.Equal(t, http.StatusOK, teams.result.StatusCode)
+	assert.NoError(t, teams.result.Err)
+
+	membership := instrumentation.calls[1]
+	assert.Equal(t, "preset.GetWorkspaceMembership", membership.info.Operation)
+	assert.Equal(t, 12, membership.info.TeamID)
+	assert.Equal(t, 34, membership.info.WorkspaceID)
+	assert.Equal(t, http.StatusInternalServerError, membership.result.StatusCode)
+
+	assert.Len(t, instrumentation.attempts, 2)
+	assert.Equal(t, 1, instrumentation.attempts[0].info.Attempt)
+	assert.Equal(t, "preset.GetAllTeams", instrumentation.attempts[0].parent)
+}
+
+func TestInstrumentation_AttemptsWithinCall(t *testing.T) {
+	requests := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	instrumentation := &recordingInstrumentation{}
+	client := &PresetClient{
+		BaseURL:         mockServer.URL,
+		HTTPClient:      &http.Client{Timeout: 10 * time.Second},
+		Token:           "mockAccessToken",
+		Instrumentation: instrumentation,
+		Retry:           &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Cache:           NewResponseCache(map[string]time.Duration{"GetAllWorkspaces": time.Minute}),
+	}
+
+	_, err := client.GetAllWorkspaces(12, nil)
+	assert.NoError(t, err)
+
+	assert.Len(t, instrumentation.calls, 1)
+	assert.Equal(t, "preset.GetAllWorkspaces", instrumentation.calls[0].info.Operation)
+	assert.Equal(t, 12, instrumentation.calls[0].info.TeamID)
+	assert.Equal(t, http.StatusOK, instrumentation.calls[0].result.StatusCode)
+
+	assert.Len(t, instrumentation.attempts, 2)
+	for i, attempt := range instrumentation.attempts {
+		assert.Equal(t, i+1, attempt.info.Attempt)
+		assert.Equal(t, "preset.GetAllWorkspaces", attempt.parent)
+		assert.Equal(t, 12, attempt.info.TeamID)
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, instrumentation.attempts[0].result.StatusCode)
+
+	// A call answered from cache is reported without any attempt
+	_, err = client.GetAllWorkspaces(12, nil)
+	assert.NoError(t, err)
+	assert.Len(t, instrumentation.calls, 2)
+	assert.Len(t, instrumentation.attempts, 2)
+}
+
+func TestInstrumentation_SupersetScope(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 7, "result": {"id": 7, "dashboard_title": "Sales"}}`))
+	}))
+	defer mockServer.Close()
+
+	instrumentation := &recordingInstrumentation{}
+	client := &PresetClient{
+		BaseURL:         mockServer.URL,
+		HTTPClient:      &http.Client{Timeout: 10 * time.Second},
+		Token:           "mockAccessToken",
+		Instrumentation: instrumentation,
+	}
+	superset := client.NewSupersetClient(Workspace{ID: 34, TeamID: 12, Hostname: "example.app.preset.io"}, nil)
+	superset.BaseURL = mockServer.URL
+
+	_, err := superset.GetDashboard(context.Background(), 7)
+	assert.NoError(t, err)
+
+	assert.Len(t, instrumentation.calls, 1)
+	call := instrumentation.calls[0]
+	assert.Equal(t, "preset.GetDashboard", call.info.Operation)
+	assert.Equal(t, 12, call.info.TeamID)
+	assert.Equal(t, 34, call.info.WorkspaceID)
+}
+
+func TestInstrumentation_UnknownOperation(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://example.com/v2/unknown?secret=abc", nil)
+	call := describeCall(req)
+
+	assert.Equal(t, "preset.HTTP GET", call.Operation)
+	assert.NotContains(t, call.URL, "abc")
+	assert.Equal(t, 0, call.TeamID)
+}
+
+func TestInstrumentation_OperationFromContext(t *testing.T) {
+	ctx := withOperation(context.Background(), "GetWorkspaceMembership", 12, 34)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/any/path", nil)
+	call := describeCall(req)
+
+	assert.Equal(t, CallInfo{
+		Operation:   "preset.GetWorkspaceMembership",
+		Method:      "GET",
+		URL:         "https://example.com/any/path",
+		TeamID:      12,
+		WorkspaceID: 34,
+	}, call)
+}
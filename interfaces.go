@@ -0,0 +1,40 @@
+package preset
+
+// TeamsAPI is the part of PresetClient dealing with teams, so callers can be tested with a mock
+type TeamsAPI interface {
+	GetAllTeams(authToken *string) (*[]Team, error)
+	GetTeamFeatureFlags(teamID int, authToken *string) (*Flags, error)
+	UpdateTeamFeatureFlags(teamID int, flags map[string]interface{}, authToken *string) (*Flags, error)
+}
+
+// WorkspacesAPI is the part of PresetClient dealing with workspaces
+type WorkspacesAPI interface {
+	GetAllWorkspaces(teamID int, authToken *string) (*[]Workspace, error)
+	CreateWorkspace(teamID int, payload WorkspacePayload, authToken *string) (*Workspace, error)
+	UpdateWorkspaceSettings(teamID int, workspaceID int, settings WorkspaceSettings, authToken *string) (*Workspace, error)
+	DeleteWorkspace(teamID int, workspaceID int, authToken *string) error
+}
+
+// MembershipsAPI is the part of PresetClient dealing with team and workspace members and invites
+type MembershipsAPI interface {
+	GetTeamMembership(teamID int, workspaceID int, authToken *string) (*[]TeamMembership, error)
+	UpdateUserTeamRole(teamID int, userID int, roleID TeamRoleEnum, authToken *string) (*TeamMembership, error)
+	DeleteTeamMembership(teamID int, userID int, authToken *string) error
+	GetWorkspaceMembership(teamID int, workspaceID int, authToken *string) (*[]WorkspaceMembership, error)
+	UpdateUserWorkspaceRole(teamID int, workspaceID int, userID int, roleIdentifier string, authToken *string) (*WorkspaceMembership, error)
+	SendInvites(teamID int, invites []Invite, authToken *string) (*[]TeamInvite, error)
+}
+
+// ManagerAPI is everything PresetClient offers for managing teams, workspaces and their members
+type ManagerAPI interface {
+	TeamsAPI
+	WorkspacesAPI
+	MembershipsAPI
+}
+
+var (
+	_ TeamsAPI       = (*PresetClient)(nil)
+	_ WorkspacesAPI  = (*PresetClient)(nil)
+	_ MembershipsAPI = (*PresetClient)(nil)
+	_ ManagerAPI     = (*PresetClient)(nil)
+)
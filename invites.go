@@ -0,0 +1,44 @@
+package preset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Invites people to a team by email, returning the invites created
+func (c *PresetClient) SendInvites(teamID int, invites []Invite, authToken *string) (*[]TeamInvite, error) {
+	if len(invites) == 0 {
+		return nil, fmt.Errorf("no invites to send")
+	}
+	for _, invite := range invites {
+		if invite.Email == "" {
+			return nil, fmt.Errorf("missing invite email")
+		}
+		if invite.TeamRoleID != TEAM_ADMIN && invite.TeamRoleID != TEAM_USER {
+			return nil, fmt.Errorf("invalid role ID for %s", invite.Email)
+		}
+	}
+
+	payloadBytes, err := c.codec().Marshal(map[string]interface{}{
+		"invites": invites,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "SendInvites", teamID, 0), "POST", fmt.Sprintf("%s/v1/teams/%d/invites/many", c.BaseURL, teamID), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	tir := TeamInviteResponse{}
+	err = c.doDecode(req, authToken, &tir)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := tir.Payload
+	return &sent, nil
+}
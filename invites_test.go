@@ -0,0 +1,60 @@
+package preset
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendInvites_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/v1/teams/1/invites/many", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"invites": [
+			{"email": "ada@example.com", "team_role_id": 2},
+			{"email": "bob@example.com", "team_role_id": 1, "workspace_id": 9, "workspace_role": "PresetAlpha"}
+		]}`, string(body))
+
+		w.Write([]byte(`{"payload": [
+			{"id": 1, "email": "ada@example.com", "team_id": 1, "team_role_id": 2, "expiration_date": "2024-01-08T00:00:00Z", "accepted_date": null},
+			{"id": 2, "email": "bob@example.com", "team_id": 1, "team_role_id": 1, "expiration_date": "2024-01-08T00:00:00Z", "accepted_date": null}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	invites, err := client.SendInvites(1, []Invite{
+		{Email: "ada@example.com", TeamRoleID: TEAM_USER},
+		{Email: "bob@example.com", TeamRoleID: TEAM_ADMIN, WorkspaceID: 9, WorkspaceRole: "PresetAlpha"},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, *invites, 2)
+	assert.Equal(t, TEAM_ADMIN, (*invites)[1].TeamRoleID)
+	assert.Equal(t, 2024, (*invites)[0].ExpirationDate.Time.Year())
+	assert.True(t, (*invites)[0].AcceptedDate.IsZero())
+}
+
+func TestSendInvites_Validation(t *testing.T) {
+	client := &PresetClient{}
+
+	_, err := client.SendInvites(1, nil, nil)
+	assert.Error(t, err)
+
+	_, err = client.SendInvites(1, []Invite{{Email: "ada@example.com", TeamRoleID: 5}}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid role ID")
+
+	_, err = client.SendInvites(1, []Invite{{TeamRoleID: TEAM_USER}}, nil)
+	assert.Error(t, err)
+}
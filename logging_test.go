@@ -0,0 +1,156 @@
+package preset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Decodes the JSON log records written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	records := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		record := map[string]interface{}{}
+		err := json.Unmarshal([]byte(line), &record)
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogging_RedactsAuthSecrets(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful authentication response
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": {"access_token": "mockAccessToken"}}`))
+	}))
+	defer mockServer.Close()
+
+	var buf bytes.Buffer
+	client := &PresetClient{
+		BaseURL:      mockServer.URL,
+		HTTPClient:   &http.Client{Timeout: 10 * time.Second},
+		Auth:         AuthStruct{TokenName: "mockTokenName", Secret: "mockSecret"},
+		Logger:       slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogBodyLimit: 1024,
+	}
+
+	authResponse, err := client.GetAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "mockAccessToken", authResponse.Payload.AccessToken)
+
+	assert.NotContains(t, buf.String(), "mockSecret")
+	assert.NotContains(t, buf.String(), "mockAccessToken")
+
+	records := logRecords(t, &buf)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "POST", records[0]["method"])
+	assert.Equal(t, float64(200), records[0]["status"])
+	assert.Equal(t, float64(1), records[0]["attempt"])
+	assert.Equal(t, "req-123", records[0]["request_id"])
+	assert.Equal(t, `{"name":"mockTokenName","secret":"REDACTED"}`, records[0]["request_body"])
+	assert.Equal(t, `{"payload":{"access_token":"REDACTED"}}`, records[0]["response_body"])
+}
+
+func TestLogging_RedactsHeadersAndPasswords(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 422 response echoing a database URI
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": {"sqlalchemy_uri": ["Could not connect to postgresql://admin:hunter2@db/warehouse"]}}`))
+	}))
+	defer mockServer.Close()
+
+	var buf bytes.Buffer
+	client := &PresetClient{
+		BaseURL:      mockServer.URL,
+		HTTPClient:   &http.Client{Timeout: 10 * time.Second},
+		Logger:       slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogBodyLimit: 1024,
+	}
+	token := "Bearer mockAccessToken"
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client, AuthToken: &token}
+
+	req, _ := superset.newRequest(context.Background(), "CreateDatabase", "POST", "/api/v1/database/", map[string]interface{}{
+		"database_name":  "warehouse",
+		"sqlalchemy_uri": "postgresql://admin:hunter2@db/warehouse",
+		"password":       "hunter2",
+	})
+	_, err := superset.doRequest(req)
+	assert.Error(t, err)
+
+	// The caller still gets the full, unredacted error body
+	assert.Contains(t, err.Error(), "hunter2")
+
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), "mockAccessToken")
+
+	records := logRecords(t, &buf)
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, float64(422), records[0]["status"])
+	assert.Equal(t, "REDACTED", records[0]["request_headers"].(map[string]interface{})["Authorization"])
+}
+
+func TestLogging_TruncatesBodies(t *testing.T) {
+	// Bodies are redacted as a whole before they are cut
+	assert.Equal(t, `{"name":"warehouse","password"...(truncated)`, redactBody([]byte(`{"name": "warehouse", "password": "hunter2"}`), "application/json", 30))
+	assert.Equal(t, `{"more":1,"name":"x","password":"REDACTED"}`, redactBody([]byte(`{"name": "x", "password": "hunter2", "more": 1}`), "application/json", 100))
+	assert.Equal(t, "<multipart/form-data body omitted>", redactBody([]byte("--boundary\r\npasswords"), "multipart/form-data; boundary=boundary", 100))
+}
+
+func TestLogging_RedactsNestedSecrets(t *testing.T) {
+	body := []byte(`{"ssh_tunnel_passwords": {"databases/x.yaml": "hunter2"}, "passwords": ["hunter3"], "overwrite": true}`)
+	assert.Equal(t, `{"overwrite":true,"passwords":"REDACTED","ssh_tunnel_passwords":"REDACTED"}`, redactBody(body, "application/json", 1024))
+
+	// A cut or otherwise broken body is left out instead of being searched for secrets
+	assert.Equal(t, "<unparseable body omitted>", redactBody(body[:60], "application/json", 1024))
+	assert.Equal(t, "<unparseable body omitted>", redactBody(body[:60], "", 1024))
+
+	res := &http.Response{Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(bytes.NewReader(body))}
+	assert.Equal(t, "<body over 40 bytes omitted>", peekResponseBody(res, 40))
+	unread, _ := io.ReadAll(res.Body)
+	assert.Equal(t, body, unread)
+}
+
+func TestLogging_BodiesOffByDefault(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	var buf bytes.Buffer
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Logger:     slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	teams, err := client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.Len(t, *teams, 0)
+
+	records := logRecords(t, &buf)
+	assert.NotContains(t, records[0], "response_body")
+	assert.Contains(t, records[0]["url"], "/v1/teams")
+}
+
+func TestIsSecretKey(t *testing.T) {
+	for _, key := range []string{"guest_token", "encrypted_extra", "Password", "api_key"} {
+		assert.True(t, IsSecretKey(key), key)
+	}
+	assert.False(t, IsSecretKey("overwrite"))
+}
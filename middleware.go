@@ -0,0 +1,78 @@
+package preset
+
+import (
+	"net/http"
+)
+
+// Doer sends HTTP requests. *http.Client is a Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to a Doer
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer a PresetClient sends its requests with, e.g. to add headers or sign
+// requests. A Middleware must not return a non-2xx response as an error; the client turns it
+// into one.
+//
+// Every request, including the auth call in GetAccessToken, passes through the chain below,
+// outermost first:
+//
+//	Instrumentation  reports the call as a whole (PresetClient.Instrumentation)
+//	Cache            answers GET requests from cache when it can (PresetClient.Cache)
+//	Retry            resends the request when it fails transiently (PresetClient.Retry)
+//	RateLimiter      waits for a token before each attempt (PresetClient.RateLimiter)
+//	Middlewares      in slice order, so the first one sees the request first (PresetClient.Middlewares)
+//	Instrumentation  reports each attempt within the call (PresetClient.Instrumentation)
+//	Logger           logs each attempt as sent (PresetClient.Logger)
+//	HTTPClient       sends it
+//
+// Middlewares therefore run once per attempt, and see the attempt number in the request context.
+type Middleware func(next Doer) Doer
+
+// Returns a Middleware applying middlewares in order, the first one outermost
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next Doer) Doer {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// Returns the Doer requests are sent with, wrapped in the middlewares configured on c
+func (c *PresetClient) doer() Doer {
+	middlewares := []Middleware{}
+	if c.Instrumentation != nil {
+		middlewares = append(middlewares, InstrumentationMiddleware(c.Instrumentation))
+	}
+	if c.Cache != nil {
+		middlewares = append(middlewares, cacheMiddleware(c.Cache, c.MaxBodySize))
+	}
+	if c.Retry != nil {
+		middlewares = append(middlewares, RetryMiddleware(*c.Retry))
+	}
+	if c.RateLimiter != nil {
+		middlewares = append(middlewares, RateLimitMiddleware(c.RateLimiter))
+	}
+	middlewares = append(middlewares, c.Middlewares...)
+	if c.Instrumentation != nil {
+		middlewares = append(middlewares, AttemptInstrumentationMiddleware(c.Instrumentation))
+	}
+	if c.Logger != nil {
+		middlewares = append(middlewares, LoggingMiddleware(c.Logger, c.LogBodyLimit))
+	}
+
+	var doer Doer = c.HTTPClient
+	if c.HTTPClient == nil {
+		doer = http.DefaultClient
+	}
+
+	return Chain(middlewares...)(doer)
+}
@@ -0,0 +1,122 @@
+package preset
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a Middleware appending name to order and setting a header when it sees a request
+func recordingMiddleware(name string, order *[]string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			*order = append(*order, name)
+			req.Header.Add("X-Middleware", name)
+			return next.Do(req)
+		})
+	}
+}
+
+func TestMiddleware_WrapsAuthCall(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/auth", r.URL.Path)
+		assert.Equal(t, []string{"first", "second"}, r.Header.Values("X-Middleware"))
+		w.Write([]byte(`{"payload": {"access_token": "mockAccessToken"}}`))
+	}))
+	defer mockServer.Close()
+
+	order := []string{}
+	client := &PresetClient{
+		BaseURL:     mockServer.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		Auth:        AuthStruct{TokenName: "name", Secret: "secret"},
+		Middlewares: []Middleware{recordingMiddleware("first", &order), recordingMiddleware("second", &order)},
+	}
+
+	ar, err := client.GetAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "mockAccessToken", ar.Payload.AccessToken)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestNewClient_OptionsApplyToAuthCall(t *testing.T) {
+	attempts := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		assert.Equal(t, []string{"first"}, r.Header.Values("X-Middleware"))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"payload": {"access_token": "mockAccessToken"}}`))
+	}))
+	defer mockServer.Close()
+
+	order := []string{}
+	tokenName, secret := "name", "secret"
+	client, err := NewClient(&mockServer.URL, &tokenName, &secret,
+		WithMiddlewares(recordingMiddleware("first", &order)),
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "mockAccessToken", client.Token)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{"first", "first"}, order)
+}
+
+func TestMiddleware_RunsOncePerAttempt(t *testing.T) {
+	calls := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	attempts := []int{}
+	order := []string{}
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Retry:      &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Middlewares: []Middleware{
+			recordingMiddleware("custom", &order),
+			func(next Doer) Doer {
+				return DoerFunc(func(req *http.Request) (*http.Response, error) {
+					attempts = append(attempts, attemptFrom(req.Context()))
+					return next.Do(req)
+				})
+			},
+		},
+	}
+
+	_, err := client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []int{1, 2}, attempts)
+	assert.Equal(t, []string{"custom", "custom"}, order)
+}
+
+func TestChain_Order(t *testing.T) {
+	order := []string{}
+	doer := Chain(recordingMiddleware("outer", &order), recordingMiddleware("inner", &order))(
+		DoerFunc(func(req *http.Request) (*http.Response, error) {
+			order = append(order, "doer")
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}))
+
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	_, err := doer.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner", "doer"}, order)
+}
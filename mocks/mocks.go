@@ -0,0 +1,189 @@
+// Package mocks provides hand-written mocks of the preset client interfaces.
+//
+// Client implements preset.ManagerAPI. Each method calls the function field of the same name
+// with a Func suffix, and returns an error if it isn't set. Every call is recorded:
+//
+//	client := &mocks.Client{
+//		GetAllTeamsFunc: func(authToken *string) (*[]preset.Team, error) {
+//			return &[]preset.Team{{ID: 1, Name: "acme"}}, nil
+//		},
+//	}
+//	service := NewService(client)
+//	...
+//	calls := client.CallsTo("GetAllTeams")
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+// Call is a method call received by a mock
+type Call struct {
+	Method string
+	// Arguments in order, excluding the auth token
+	Args []interface{}
+}
+
+// Client is a mock of PresetClient's team, workspace and membership methods
+type Client struct {
+	GetAllTeamsFunc             func(authToken *string) (*[]preset.Team, error)
+	GetTeamFeatureFlagsFunc     func(teamID int, authToken *string) (*preset.Flags, error)
+	UpdateTeamFeatureFlagsFunc  func(teamID int, flags map[string]interface{}, authToken *string) (*preset.Flags, error)
+	GetAllWorkspacesFunc        func(teamID int, authToken *string) (*[]preset.Workspace, error)
+	CreateWorkspaceFunc         func(teamID int, payload preset.WorkspacePayload, authToken *string) (*preset.Workspace, error)
+	UpdateWorkspaceSettingsFunc func(teamID int, workspaceID int, settings preset.WorkspaceSettings, authToken *string) (*preset.Workspace, error)
+	DeleteWorkspaceFunc         func(teamID int, workspaceID int, authToken *string) error
+	GetTeamMembershipFunc       func(teamID int, workspaceID int, authToken *string) (*[]preset.TeamMembership, error)
+	UpdateUserTeamRoleFunc      func(teamID int, userID int, roleID preset.TeamRoleEnum, authToken *string) (*preset.TeamMembership, error)
+	DeleteTeamMembershipFunc    func(teamID int, userID int, authToken *string) error
+	GetWorkspaceMembershipFunc  func(teamID int, workspaceID int, authToken *string) (*[]preset.WorkspaceMembership, error)
+	UpdateUserWorkspaceRoleFunc func(teamID int, workspaceID int, userID int, roleIdentifier string, authToken *string) (*preset.WorkspaceMembership, error)
+	SendInvitesFunc             func(teamID int, invites []preset.Invite, authToken *string) (*[]preset.TeamInvite, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var (
+	_ preset.TeamsAPI       = (*Client)(nil)
+	_ preset.WorkspacesAPI  = (*Client)(nil)
+	_ preset.MembershipsAPI = (*Client)(nil)
+	_ preset.ManagerAPI     = (*Client)(nil)
+)
+
+// Returns the calls received so far, oldest first
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call{}, m.calls...)
+}
+
+// Returns the calls received so far to the given method
+func (m *Client) CallsTo(method string) []Call {
+	calls := []Call{}
+	for _, call := range m.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (m *Client) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func notStubbed(method string) error {
+	return fmt.Errorf("mocks: %s called but %sFunc is not set", method, method)
+}
+
+func (m *Client) GetAllTeams(authToken *string) (*[]preset.Team, error) {
+	m.record("GetAllTeams")
+	if m.GetAllTeamsFunc == nil {
+		return nil, notStubbed("GetAllTeams")
+	}
+	return m.GetAllTeamsFunc(authToken)
+}
+
+func (m *Client) GetTeamFeatureFlags(teamID int, authToken *string) (*preset.Flags, error) {
+	m.record("GetTeamFeatureFlags", teamID)
+	if m.GetTeamFeatureFlagsFunc == nil {
+		return nil, notStubbed("GetTeamFeatureFlags")
+	}
+	return m.GetTeamFeatureFlagsFunc(teamID, authToken)
+}
+
+func (m *Client) UpdateTeamFeatureFlags(teamID int, flags map[string]interface{}, authToken *string) (*preset.Flags, error) {
+	m.record("UpdateTeamFeatureFlags", teamID, flags)
+	if m.UpdateTeamFeatureFlagsFunc == nil {
+		return nil, notStubbed("UpdateTeamFeatureFlags")
+	}
+	return m.UpdateTeamFeatureFlagsFunc(teamID, flags, authToken)
+}
+
+func (m *Client) GetAllWorkspaces(teamID int, authToken *string) (*[]preset.Workspace, error) {
+	m.record("GetAllWorkspaces", teamID)
+	if m.GetAllWorkspacesFunc == nil {
+		return nil, notStubbed("GetAllWorkspaces")
+	}
+	return m.GetAllWorkspacesFunc(teamID, authToken)
+}
+
+func (m *Client) CreateWorkspace(teamID int, payload preset.WorkspacePayload, authToken *string) (*preset.Workspace, error) {
+	m.record("CreateWorkspace", teamID, payload)
+	if m.CreateWorkspaceFunc == nil {
+		return nil, notStubbed("CreateWorkspace")
+	}
+	return m.CreateWorkspaceFunc(teamID, payload, authToken)
+}
+
+func (m *Client) UpdateWorkspaceSettings(teamID int, workspaceID int, settings preset.WorkspaceSettings, authToken *string) (*preset.Workspace, error) {
+	m.record("UpdateWorkspaceSettings", teamID, workspaceID, settings)
+	if m.UpdateWorkspaceSettingsFunc == nil {
+		return nil, notStubbed("UpdateWorkspaceSettings")
+	}
+	return m.UpdateWorkspaceSettingsFunc(teamID, workspaceID, settings, authToken)
+}
+
+func (m *Client) DeleteWorkspace(teamID int, workspaceID int, authToken *string) error {
+	m.record("DeleteWorkspace", teamID, workspaceID)
+	if m.DeleteWorkspaceFunc == nil {
+		return notStubbed("DeleteWorkspace")
+	}
+	return m.DeleteWorkspaceFunc(teamID, workspaceID, authToken)
+}
+
+func (m *Client) GetTeamMembership(teamID int, workspaceID int, authToken *string) (*[]preset.TeamMembership, error) {
+	m.record("GetTeamMembership", teamID, workspaceID)
+	if m.GetTeamMembershipFunc == nil {
+		return nil, notStubbed("GetTeamMembership")
+	}
+	return m.GetTeamMembershipFunc(teamID, workspaceID, authToken)
+}
+
+func (m *Client) UpdateUserTeamRole(teamID int, userID int, roleID preset.TeamRoleEnum, authToken *string) (*preset.TeamMembership, error) {
+	m.record("UpdateUserTeamRole", teamID, userID, roleID)
+	if m.UpdateUserTeamRoleFunc == nil {
+		return nil, notStubbed("UpdateUserTeamRole")
+	}
+	return m.UpdateUserTeamRoleFunc(teamID, userID, roleID, authToken)
+}
+
+func (m *Client) DeleteTeamMembership(teamID int, userID int, authToken *string) error {
+	m.record("DeleteTeamMembership", teamID, userID)
+	if m.DeleteTeamMembershipFunc == nil {
+		return notStubbed("DeleteTeamMembership")
+	}
+	return m.DeleteTeamMembershipFunc(teamID, userID, authToken)
+}
+
+func (m *Client) GetWorkspaceMembership(teamID int, workspaceID int, authToken *string) (*[]preset.WorkspaceMembership, error) {
+	m.record("GetWorkspaceMembership", teamID, workspaceID)
+	if m.GetWorkspaceMembershipFunc == nil {
+		return nil, notStubbed("GetWorkspaceMembership")
+	}
+	return m.GetWorkspaceMembershipFunc(teamID, workspaceID, authToken)
+}
+
+func (m *Client) UpdateUserWorkspaceRole(teamID int, workspaceID int, userID int, roleIdentifier string, authToken *string) (*preset.WorkspaceMembership, error) {
+	m.record("UpdateUserWorkspaceRole", teamID, workspaceID, userID, roleIdentifier)
+	if m.UpdateUserWorkspaceRoleFunc == nil {
+		return nil, notStubbed("UpdateUserWorkspaceRole")
+	}
+	return m.UpdateUserWorkspaceRoleFunc(teamID, workspaceID, userID, roleIdentifier, authToken)
+}
+
+func (m *Client) SendInvites(teamID int, invites []preset.Invite, authToken *string) (*[]preset.TeamInvite, error) {
+	m.record("SendInvites", teamID, invites)
+	if m.SendInvitesFunc == nil {
+		return nil, notStubbed("SendInvites")
+	}
+	return m.SendInvitesFunc(teamID, invites, authToken)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+// Stands in for downstream code depending on the interfaces
+func promoteAll(api preset.MembershipsAPI, teamID int) error {
+	memberships, err := api.GetTeamMembership(teamID, 0, nil)
+	if err != nil {
+		return err
+	}
+	for _, membership := range *memberships {
+		if _, err := api.UpdateUserTeamRole(teamID, membership.User.ID, preset.TEAM_ADMIN, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestClient_Stubs(t *testing.T) {
+	client := &Client{
+		GetTeamMembershipFunc: func(teamID int, workspaceID int, authToken *string) (*[]preset.TeamMembership, error) {
+			return &[]preset.TeamMembership{{User: preset.User{ID: 7}}, {User: preset.User{ID: 8}}}, nil
+		},
+		UpdateUserTeamRoleFunc: func(teamID int, userID int, roleID preset.TeamRoleEnum, authToken *string) (*preset.TeamMembership, error) {
+			return &preset.TeamMembership{User: preset.User{ID: userID}}, nil
+		},
+	}
+
+	assert.NoError(t, promoteAll(client, 1))
+
+	calls := client.CallsTo("UpdateUserTeamRole")
+	assert.Len(t, calls, 2)
+	assert.Equal(t, []interface{}{1, 8, preset.TEAM_ADMIN}, calls[1].Args)
+	assert.Len(t, client.Calls(), 3)
+}
+
+func TestClient_NotStubbed(t *testing.T) {
+	client := &Client{}
+
+	_, err := client.GetAllTeams(nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GetAllTeamsFunc is not set")
+	assert.Error(t, client.DeleteTeamMembership(1, 2, nil))
+}
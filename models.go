@@ -1,27 +1,33 @@
 package preset
 
+import (
+	"time"
+)
+
 type Team struct {
 	ID                    int    `json:"id"`
 	Title                 string `json:"title"`
 	AdminCount            int    `json:"admin_count"`
 	BillingMethod         string `json:"billing_method"`
-	BillingStatus         string `json:"billing_status"`
-	CreatedOn             string `json:"created_on"`
+	BillingStatus         BillingStatus `json:"billing_status"`
+	CreatedOn             Timestamp `json:"created_on"`
 	DefaultWorkspaceRole  Role   `json:"default_workspace_role"`
-	DowngradedAt          string `json:"downgraded_at"`
+	DowngradedAt          Timestamp `json:"downgraded_at"`
 	FeatureFlags          Flags  `json:"feature_flags"`
 	Name                  string `json:"name"`
 	PendingPurchaseType   string `json:"pending_purchase_type"`
 	PlanCode              string `json:"plan_code"`
 	RecurlyAccountID      string `json:"recurly_account_id"`
-	SubscriptionStatus    string `json:"subscription_status"`
-	Tier                  string `json:"tier"`
-	TrialExpiry           string `json:"trial_expiry"`
+	SubscriptionStatus    SubscriptionStatus `json:"subscription_status"`
+	Tier                  Tier   `json:"tier"`
+	TrialExpiry           Timestamp `json:"trial_expiry"`
 	UserCount             int    `json:"user_count"`
 	WhitelistedEmailDomains []string `json:"whitelisted_email_domains"`
 	WorkspaceCount        int    `json:"workspace_count"`
 	WorkspaceLimit        int    `json:"workspace_limit"`
 	WorkspaceRoles        []Role `json:"workspace_roles"`
+	// Fields of the payload the SDK doesn't model yet, keyed by JSON name
+	Extra                 ExtraFields `json:"-"`
 }
 
 type Role struct {
@@ -43,6 +49,8 @@ type Flags struct {
 	UsageMetricsRlsRules     string `json:"usage_metrics_rls_rules"`
 	WorkspaceRegionSelectEnabled bool   `json:"workspace_region_select_enabled"`
 	WorkspaceRolesEnabled    bool   `json:"workspace_roles_enabled"`
+	// Flags not modeled above, keyed by their JSON name
+	Other                    map[string]interface{} `json:"-"`
 }
 
 type TeamResponse struct {
@@ -53,6 +61,8 @@ type TeamMembership struct {
 	IsRoleFromGroup bool     `json:"is_role_from_group,omitempty"`
 	TeamRole        TeamRole `json:"team_role"`
 	User            User     `json:"user"`
+	// Fields of the payload the SDK doesn't model yet, keyed by JSON name
+	Extra           ExtraFields `json:"-"`
 }
 
 type TeamRole struct {
@@ -67,6 +77,8 @@ type User struct {
 	LastName  string `json:"last_name"`
 	Onboarded bool   `json:"onboarded"`
 	Username  string `json:"username"`
+	// Fields of the payload the SDK doesn't model yet, keyed by JSON name
+	Extra     ExtraFields `json:"-"`
 }
 
 type TeamMembershipResponse struct {
@@ -77,15 +89,19 @@ type TeamMembershipUpdateResponse struct {
 	Payload TeamMembership `json:"payload"`
 }
 
+type TeamUpdateResponse struct {
+	Payload Team `json:"payload"`
+}
+
 type Workspace struct {
 	ID                   int    `json:"id"`
 	Accessible           bool   `json:"accessible"`
 	AiAssistActivated    bool   `json:"ai_assist_activated"`
 	AllowPublicDashboards bool  `json:"allow_public_dashboards"`
-	ChangedOn            string `json:"changed_on"`
+	ChangedOn            Timestamp `json:"changed_on"`
 	ClusterID            int    `json:"cluster_id"`
 	Color                string `json:"color"`
-	CreatedOn            string `json:"created_on"`
+	CreatedOn            Timestamp `json:"created_on"`
 	DeploymentID         int    `json:"deployment_id"`
 	Descr                string `json:"descr"`
 	Hostname             string `json:"hostname"`
@@ -96,7 +112,9 @@ type Workspace struct {
 	Status               string `json:"status"`
 	TeamID               int    `json:"team_id"`
 	Title                string `json:"title"`
-	WorkspaceStatus      string `json:"workspace_status"`
+	WorkspaceStatus      WorkspaceStatus `json:"workspace_status"`
+	// Fields of the payload the SDK doesn't model yet, keyed by JSON name
+	Extra                ExtraFields `json:"-"`
 }
 
 type WorkspaceGetResponse struct {
@@ -107,6 +125,8 @@ type WorkspaceMembership struct {
 	IsRoleFromGroup bool `json:"is_role_from_group,omitempty"`
 	User            User `json:"user"`
 	WorkspaceRole   WorkspaceRole `json:"workspace_role"`
+	// Fields of the payload the SDK doesn't model yet, keyed by JSON name
+	Extra           ExtraFields `json:"-"`
 }
 
 type WorkspaceRole struct {
@@ -121,3 +141,522 @@ type WorkspaceMembershipGetResponse struct {
 type WorkspaceMembershipUpdateResponse struct {
 	Payload WorkspaceMembership `json:"payload"`
 }
+
+type WorkspaceUpdateResponse struct {
+	Payload Workspace `json:"payload"`
+}
+
+// WorkspaceSettings holds the workspace toggles that can be changed; nil fields are left unchanged
+type WorkspaceSettings struct {
+	AiAssistActivated     *bool `json:"ai_assist_activated,omitempty"`
+	AllowPublicDashboards *bool `json:"allow_public_dashboards,omitempty"`
+}
+
+type SupersetListResponse[T any] struct {
+	Count  int `json:"count"`
+	Result []T `json:"result"`
+}
+
+type Dashboard struct {
+	ID                   int              `json:"id"`
+	DashboardTitle       string           `json:"dashboard_title"`
+	Slug                 string           `json:"slug"`
+	URL                  string           `json:"url"`
+	CSS                  string           `json:"css"`
+	PositionJSON         string           `json:"position_json"`
+	JSONMetadata         string           `json:"json_metadata"`
+	Owners               []DashboardOwner `json:"owners"`
+	Roles                []DashboardRole  `json:"roles"`
+	Published            bool             `json:"published"`
+	CertifiedBy          string           `json:"certified_by"`
+	CertificationDetails string           `json:"certification_details"`
+	ChangedOn            string           `json:"changed_on"`
+	ThumbnailURL         string           `json:"thumbnail_url"`
+}
+
+type DashboardOwner struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type DashboardRole struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// DashboardPayload holds the writable dashboard fields; nil fields are left unchanged on update
+type DashboardPayload struct {
+	DashboardTitle       *string `json:"dashboard_title,omitempty"`
+	Slug                 *string `json:"slug,omitempty"`
+	CSS                  *string `json:"css,omitempty"`
+	PositionJSON         *string `json:"position_json,omitempty"`
+	JSONMetadata         *string `json:"json_metadata,omitempty"`
+	Owners               *[]int  `json:"owners,omitempty"`
+	Roles                *[]int  `json:"roles,omitempty"`
+	Published            *bool   `json:"published,omitempty"`
+	CertifiedBy          *string `json:"certified_by,omitempty"`
+	CertificationDetails *string `json:"certification_details,omitempty"`
+}
+
+type DashboardResponse struct {
+	ID     int       `json:"id"`
+	Result Dashboard `json:"result"`
+}
+
+type DashboardMutationResponse struct {
+	ID int `json:"id"`
+}
+
+// DashboardMetadata is the subset of a dashboard's json_metadata that the SDK understands
+type DashboardMetadata struct {
+	NativeFilterConfiguration []NativeFilter `json:"native_filter_configuration"`
+}
+
+type NativeFilter struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	FilterType       string                 `json:"filterType"`
+	Type             string                 `json:"type"`
+	Description      string                 `json:"description"`
+	Targets          []NativeFilterTarget   `json:"targets"`
+	DefaultDataMask  map[string]interface{} `json:"defaultDataMask,omitempty"`
+	ControlValues    map[string]interface{} `json:"controlValues,omitempty"`
+	CascadeParentIDs []string               `json:"cascadeParentIds"`
+	Scope            NativeFilterScope      `json:"scope"`
+	ChartsInScope    []int                  `json:"chartsInScope,omitempty"`
+	TabsInScope      []string               `json:"tabsInScope,omitempty"`
+	// Filter settings the SDK doesn't model, such as requiredFirst or adhoc_filters, keyed by JSON name
+	Extra            ExtraFields `json:"-"`
+}
+
+type NativeFilterTarget struct {
+	DatasetID int                       `json:"datasetId,omitempty"`
+	Column    *NativeFilterTargetColumn `json:"column,omitempty"`
+}
+
+type NativeFilterTargetColumn struct {
+	Name string `json:"name"`
+}
+
+type NativeFilterScope struct {
+	RootPath []string `json:"rootPath"`
+	Excluded []int    `json:"excluded"`
+}
+
+type DashboardChart struct {
+	ID        int                    `json:"id"`
+	SliceName string                 `json:"slice_name"`
+	VizType   string                 `json:"viz_type"`
+	SliceURL  string                 `json:"slice_url"`
+	FormData  map[string]interface{} `json:"form_data"`
+	ChangedOn string                 `json:"changed_on"`
+}
+
+type DashboardChartsResponse struct {
+	Result []DashboardChart `json:"result"`
+}
+
+type DashboardDataset struct {
+	ID         int             `json:"id"`
+	TableName  string          `json:"table_name"`
+	Schema     string          `json:"schema"`
+	Datasource string          `json:"datasource_name"`
+	Database   DatasetDatabase `json:"database"`
+}
+
+type DatasetDatabase struct {
+	ID           int    `json:"id"`
+	DatabaseName string `json:"database_name"`
+	Backend      string `json:"backend"`
+}
+
+type DashboardDatasetsResponse struct {
+	Result []DashboardDataset `json:"result"`
+}
+
+// AssetBundle is an in-memory copy of a Superset export ZIP
+type AssetBundle struct {
+	// Name of the top-level directory of the archive, e.g. dashboard_export_20230801T120000
+	Root string
+	// File contents keyed by path relative to Root, e.g. databases/warehouse.yaml
+	Files map[string][]byte
+}
+
+// SQLOptions tunes how ExecuteSQL runs a query
+type SQLOptions struct {
+	// Run the query on the workspace's async workers and poll for its results
+	Async bool
+	// Maximum number of rows to return; 0 uses the workspace default
+	Limit int
+	// Values for the Jinja parameters referenced by the SQL
+	TemplateParams map[string]interface{}
+	// How often to check on an async query; defaults to one second
+	PollInterval time.Duration
+}
+
+// Row is a single result row keyed by column name. Numbers are decoded as json.Number.
+type Row map[string]interface{}
+
+type ResultColumn struct {
+	Name        string          `json:"name"`
+	ColumnName  string          `json:"column_name"`
+	Type        string          `json:"type"`
+	IsDttm      bool            `json:"is_dttm"`
+	TypeGeneric GenericDataType `json:"type_generic"`
+}
+
+// QueryResultHeader holds the non-row fields of a SQL Lab result
+type QueryResultHeader struct {
+	Status  QueryState     `json:"status"`
+	QueryID int            `json:"query_id"`
+	Columns []ResultColumn `json:"columns"`
+	Error   string         `json:"error"`
+}
+
+type AsyncQuery struct {
+	ClientID string     `json:"id"`
+	QueryID  int        `json:"queryId"`
+	State    QueryState `json:"state"`
+}
+
+type AsyncExecuteResponse struct {
+	Query AsyncQuery `json:"query"`
+}
+
+type Query struct {
+	ID           int        `json:"id"`
+	ClientID     string     `json:"client_id"`
+	Status       QueryState `json:"status"`
+	SQL          string     `json:"sql"`
+	ExecutedSQL  string     `json:"executed_sql"`
+	Schema       string     `json:"schema"`
+	Rows         int        `json:"rows"`
+	ResultsKey   string     `json:"results_key"`
+	ErrorMessage string     `json:"error_message"`
+	TrackingURL  string     `json:"tracking_url"`
+	StartTime    float64    `json:"start_time"`
+	EndTime      float64    `json:"end_time"`
+}
+
+type QueryResponse struct {
+	ID     int   `json:"id"`
+	Result Query `json:"result"`
+}
+
+type SavedQuery struct {
+	ID          int             `json:"id"`
+	Label       string          `json:"label"`
+	Description string          `json:"description"`
+	Schema      string          `json:"schema"`
+	SQL         string          `json:"sql"`
+	Database    DatasetDatabase `json:"database"`
+	ChangedOn   string          `json:"changed_on"`
+}
+
+// SavedQueryPayload holds the writable saved query fields; nil fields are left unchanged on update
+type SavedQueryPayload struct {
+	Label       *string `json:"label,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Schema      *string `json:"schema,omitempty"`
+	DatabaseID  *int    `json:"db_id,omitempty"`
+	SQL         *string `json:"sql,omitempty"`
+}
+
+type SavedQueryResponse struct {
+	ID     int        `json:"id"`
+	Result SavedQuery `json:"result"`
+}
+
+type RLSFilter struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	FilterType  RLSFilterType `json:"filter_type"`
+	Tables      []RLSTable    `json:"tables"`
+	Roles       []RLSRole     `json:"roles"`
+	GroupKey    string        `json:"group_key"`
+	Clause      string        `json:"clause"`
+}
+
+type RLSTable struct {
+	ID        int    `json:"id"`
+	Schema    string `json:"schema"`
+	TableName string `json:"table_name"`
+}
+
+type RLSRole struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// RLSFilterPayload holds the writable RLS filter fields; nil fields are left unchanged on update
+type RLSFilterPayload struct {
+	Name        *string        `json:"name,omitempty"`
+	Description *string        `json:"description,omitempty"`
+	FilterType  *RLSFilterType `json:"filter_type,omitempty"`
+	Tables      *[]int         `json:"tables,omitempty"`
+	Roles       *[]int         `json:"roles,omitempty"`
+	GroupKey    *string        `json:"group_key,omitempty"`
+	Clause      *string        `json:"clause,omitempty"`
+}
+
+type RLSFilterResponse struct {
+	ID     int       `json:"id"`
+	Result RLSFilter `json:"result"`
+}
+
+type RLSFilterMutationResponse struct {
+	ID int `json:"id"`
+}
+
+// RelatedItem is an entry of a Superset "related" endpoint, used to look up IDs by name
+type RelatedItem struct {
+	Value int    `json:"value"`
+	Text  string `json:"text"`
+}
+
+type SecurityRole struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type SecurityRoleResponse struct {
+	ID     int          `json:"id"`
+	Result SecurityRole `json:"result"`
+}
+
+// PermissionView is a permission granted on a view menu, e.g. datasource_access on [warehouse].[orders](id:12)
+type PermissionView struct {
+	Permission string
+	ViewMenu   string
+}
+
+type PermissionViewMenu struct {
+	ID         int            `json:"id"`
+	Permission PermissionName `json:"permission"`
+	ViewMenu   PermissionName `json:"view_menu"`
+}
+
+type PermissionName struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ReportSchedule struct {
+	ID                  int               `json:"id"`
+	Type                ReportType        `json:"type"`
+	Name                string            `json:"name"`
+	Description         string            `json:"description"`
+	Active              bool              `json:"active"`
+	Crontab             string            `json:"crontab"`
+	Timezone            string            `json:"timezone"`
+	Recipients          []ReportRecipient `json:"recipients"`
+	Chart               *ReportChart      `json:"chart"`
+	Dashboard           *ReportDashboard  `json:"dashboard"`
+	Database            *DatasetDatabase  `json:"database"`
+	SQL                 string            `json:"sql"`
+	ValidatorType       ValidatorType     `json:"validator_type"`
+	ValidatorConfigJSON *ValidatorConfig  `json:"validator_config_json"`
+	WorkingTimeout      int               `json:"working_timeout"`
+	GracePeriod         int               `json:"grace_period"`
+	LogRetention        int               `json:"log_retention"`
+	ReportFormat        string            `json:"report_format"`
+	Owners              []DashboardOwner  `json:"owners"`
+	LastState           ReportState       `json:"last_state"`
+	LastEvalDttm        Timestamp         `json:"last_eval_dttm"`
+}
+
+type ReportRecipient struct {
+	Type                RecipientType         `json:"type"`
+	RecipientConfigJSON ReportRecipientConfig `json:"recipient_config_json"`
+}
+
+type ReportRecipientConfig struct {
+	// Comma-separated email addresses or Slack channels
+	Target string `json:"target"`
+}
+
+type ReportChart struct {
+	ID        int    `json:"id"`
+	SliceName string `json:"slice_name"`
+}
+
+type ReportDashboard struct {
+	ID             int    `json:"id"`
+	DashboardTitle string `json:"dashboard_title"`
+}
+
+type ValidatorConfig struct {
+	Op        string  `json:"op,omitempty"`
+	Threshold float64 `json:"threshold"`
+}
+
+// ReportSchedulePayload holds the writable report schedule fields; nil fields are left unchanged on update
+type ReportSchedulePayload struct {
+	Type                *ReportType        `json:"type,omitempty"`
+	Name                *string            `json:"name,omitempty"`
+	Description         *string            `json:"description,omitempty"`
+	Active              *bool              `json:"active,omitempty"`
+	Crontab             *string            `json:"crontab,omitempty"`
+	Timezone            *string            `json:"timezone,omitempty"`
+	Recipients          *[]ReportRecipient `json:"recipients,omitempty"`
+	Chart               *int               `json:"chart,omitempty"`
+	Dashboard           *int               `json:"dashboard,omitempty"`
+	Database            *int               `json:"database,omitempty"`
+	SQL                 *string            `json:"sql,omitempty"`
+	ValidatorType       *ValidatorType     `json:"validator_type,omitempty"`
+	ValidatorConfigJSON *ValidatorConfig   `json:"validator_config_json,omitempty"`
+	WorkingTimeout      *int               `json:"working_timeout,omitempty"`
+	GracePeriod         *int               `json:"grace_period,omitempty"`
+	LogRetention        *int               `json:"log_retention,omitempty"`
+	ReportFormat        *string            `json:"report_format,omitempty"`
+	Owners              *[]int             `json:"owners,omitempty"`
+}
+
+type ReportScheduleResponse struct {
+	ID     int            `json:"id"`
+	Result ReportSchedule `json:"result"`
+}
+
+type ReportExecutionLog struct {
+	ID            int         `json:"id"`
+	UUID          string      `json:"uuid"`
+	State         ReportState `json:"state"`
+	ScheduledDttm string      `json:"scheduled_dttm"`
+	StartDttm     string      `json:"start_dttm"`
+	EndDttm       string      `json:"end_dttm"`
+	Value         *float64    `json:"value"`
+	ErrorMessage  string      `json:"error_message"`
+}
+
+// AuditEventFilter narrows down the audit events returned by ListAuditEvents; zero values are ignored
+type AuditEventFilter struct {
+	Since time.Time
+	Until time.Time
+	// Email of the user who performed the action
+	Actor       string
+	Action      string
+	WorkspaceID int
+	// Cursor returned as NextCursor by the previous page
+	Cursor string
+	Limit  int
+}
+
+type AuditEvent struct {
+	ID           string                 `json:"id"`
+	Timestamp    Timestamp              `json:"timestamp"`
+	Action       string                 `json:"action"`
+	Actor        User                   `json:"actor"`
+	TeamID       int                    `json:"team_id"`
+	WorkspaceID  *int                   `json:"workspace_id"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	IPAddress    string                 `json:"ip_address"`
+	Details      map[string]interface{} `json:"details"`
+}
+
+type AuditEventPage struct {
+	Events     []AuditEvent
+	NextCursor string
+}
+
+type AuditEventMeta struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+type AuditEventResponse struct {
+	Payload []AuditEvent   `json:"payload"`
+	Meta    AuditEventMeta `json:"meta"`
+}
+
+// AuditCheckpoint records the page cursor and the last event handled from that page
+type AuditCheckpoint struct {
+	Cursor      string `json:"cursor"`
+	LastEventID string `json:"last_event_id"`
+	// Time of the last event handled, to resume from when its ID is no longer on the page
+	LastEventTime Timestamp `json:"last_event_time"`
+}
+
+type UsageMetrics struct {
+	ActiveUsers    int `json:"active_users"`
+	DashboardViews int `json:"dashboard_views"`
+	QueryCount     int `json:"query_count"`
+}
+
+type WorkspaceUsage struct {
+	UsageMetrics
+	WorkspaceID   int    `json:"workspace_id"`
+	StartDate     string `json:"start_date"`
+	EndDate       string `json:"end_date"`
+	ActiveUserIDs []int  `json:"active_user_ids,omitempty"`
+}
+
+// UsageDashboard is the usage metrics dashboard Preset provisions for a team, located by its feature flags
+type UsageDashboard struct {
+	// Base URL of the workspace serving the dashboard, from usage_metrics_link
+	WorkspaceURL string `json:"workspace_url"`
+	// ID or slug of the dashboard, from usage_metrics_resource_id
+	ResourceID string `json:"resource_id"`
+	// Value of the usage_metrics_rls_rules flag, reported as is; the SDK doesn't apply it to queries
+	RLSRules string `json:"rls_rules"`
+}
+
+// UsageColumns names the columns of the usage dashboard's dataset that usage metrics are computed from
+type UsageColumns struct {
+	Time      string
+	Workspace string
+	User      string
+	Action    string
+	// Values of the Action column that count as a dashboard view and as a query
+	DashboardViewAction string
+	QueryAction         string
+}
+
+type ChartDataResponse struct {
+	Result []ChartDataResult `json:"result"`
+}
+
+type ChartDataResult struct {
+	Colnames []string                 `json:"colnames"`
+	Data     []map[string]interface{} `json:"data"`
+}
+
+type TeamUsage struct {
+	TeamID     int              `json:"team_id"`
+	Workspaces []WorkspaceUsage `json:"workspaces"`
+}
+
+// WorkspacePayload holds the fields a workspace is created with
+type WorkspacePayload struct {
+	Title  string `json:"title"`
+	Region string `json:"region,omitempty"`
+	Descr  string `json:"descr,omitempty"`
+}
+
+type WorkspaceCreateResponse struct {
+	Payload Workspace `json:"payload"`
+}
+
+// Invite asks someone to join a team, and optionally a workspace
+type Invite struct {
+	Email      string       `json:"email"`
+	TeamRoleID TeamRoleEnum `json:"team_role_id"`
+	// Workspace to add the invitee to once they accept, with a role identifier such as PresetAlpha
+	WorkspaceID   int    `json:"workspace_id,omitempty"`
+	WorkspaceRole string `json:"workspace_role,omitempty"`
+}
+
+// TeamInvite is an invite sent to a team
+type TeamInvite struct {
+	ID             int          `json:"id"`
+	Email          string       `json:"email"`
+	TeamID         int          `json:"team_id"`
+	TeamRoleID     TeamRoleEnum `json:"team_role_id"`
+	ExpirationDate Timestamp    `json:"expiration_date"`
+	AcceptedDate   Timestamp    `json:"accepted_date"`
+}
+
+type TeamInviteResponse struct {
+	Payload []TeamInvite `json:"payload"`
+}
@@ -0,0 +1,212 @@
+// Package otelpreset reports the API calls of a preset.PresetClient to OpenTelemetry.
+//
+//	client.Instrumentation = otelpreset.New(
+//		otelpreset.WithTracerProvider(tracerProvider),
+//		otelpreset.WithMeterProvider(meterProvider),
+//	)
+//
+// Each call produces a span named after the SDK method, e.g. preset.GetWorkspaceMembership, with
+// a client span for every attempt at sending its request as children. Calls answered from the
+// client's cache have no children. Every attempt is counted in the preset.client.requests,
+// preset.client.errors and preset.client.request.duration instruments.
+package otelpreset
+
+import (
+	"context"
+	"fmt"
+
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/vadivelselvaraj/preset-sdk-go/otelpreset"
+
+// Attribute keys set on spans and metrics. Team and workspace IDs are only set on spans, so that
+// the number of metric series doesn't grow with the number of teams and workspaces.
+const (
+	TeamIDKey      = attribute.Key("preset.team_id")
+	WorkspaceIDKey = attribute.Key("preset.workspace_id")
+	OperationKey   = attribute.Key("preset.operation")
+	AttemptKey     = attribute.Key("preset.attempt")
+	MethodKey      = attribute.Key("http.request.method")
+	StatusCodeKey  = attribute.Key("http.response.status_code")
+	ResendCountKey = attribute.Key("http.request.resend_count")
+	URLKey         = attribute.Key("url.full")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures the Instrumentation returned by New
+type Option func(*config)
+
+// Sets the provider spans are created with; defaults to the global one
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// Sets the provider metrics are recorded with; defaults to the global one
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Sets the propagator used to inject trace context into requests; defaults to the global one
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Instrumentation implements preset.Instrumentation with OpenTelemetry
+type Instrumentation struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	requests   metric.Int64Counter
+	errors     metric.Int64Counter
+	duration   metric.Float64Histogram
+}
+
+var _ preset.Instrumentation = (*Instrumentation)(nil)
+
+// Returns an Instrumentation reporting to the configured providers
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(scopeName)
+
+	requests, err := meter.Int64Counter("preset.client.requests",
+		metric.WithDescription("Number of requests sent to the Preset APIs"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, fmt.Errorf("creating requests counter: %w", err)
+	}
+
+	errors, err := meter.Int64Counter("preset.client.errors",
+		metric.WithDescription("Number of requests that failed or returned an error status"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, fmt.Errorf("creating errors counter: %w", err)
+	}
+
+	duration, err := meter.Float64Histogram("preset.client.request.duration",
+		metric.WithDescription("Latency of requests sent to the Preset APIs"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("creating duration histogram: %w", err)
+	}
+
+	return &Instrumentation{
+		tracer:     cfg.tracerProvider.Tracer(scopeName),
+		propagator: cfg.propagator,
+		requests:   requests,
+		errors:     errors,
+		duration:   duration,
+	}, nil
+}
+
+// Starts the span of the call, which the spans of its attempts are children of
+func (i *Instrumentation) StartCall(ctx context.Context, call preset.CallInfo) (context.Context, func(preset.CallResult)) {
+	attrs := append([]attribute.KeyValue{URLKey.String(call.URL)}, spanAttributes(call)...)
+
+	ctx, span := i.tracer.Start(ctx, call.Operation,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...))
+
+	return ctx, func(result preset.CallResult) {
+		defer span.End()
+		endSpan(span, result)
+	}
+}
+
+// Starts a client span for the attempt, propagates it in the request's headers and records the
+// attempt's metrics once it completes
+func (i *Instrumentation) StartAttempt(ctx context.Context, attempt preset.AttemptInfo) (context.Context, func(preset.CallResult)) {
+	attrs := metricAttributes(attempt.CallInfo)
+
+	spanAttrs := append([]attribute.KeyValue{URLKey.String(attempt.URL), AttemptKey.Int(attempt.Attempt)}, spanAttributes(attempt.CallInfo)...)
+	if attempt.Attempt > 1 {
+		spanAttrs = append(spanAttrs, ResendCountKey.Int(attempt.Attempt-1))
+	}
+
+	ctx, span := i.tracer.Start(ctx, attempt.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...))
+
+	if attempt.Header != nil {
+		i.propagator.Inject(ctx, propagation.HeaderCarrier(attempt.Header))
+	}
+
+	return ctx, func(result preset.CallResult) {
+		defer span.End()
+		failed := endSpan(span, result)
+
+		if result.StatusCode != 0 {
+			attrs = append(attrs, StatusCodeKey.Int(result.StatusCode))
+		}
+
+		set := metric.WithAttributes(attrs...)
+		i.requests.Add(ctx, 1, set)
+		i.duration.Record(ctx, result.Duration.Seconds(), set)
+		if failed {
+			i.errors.Add(ctx, 1, set)
+		}
+	}
+}
+
+// Returns the attributes of the metrics of a call, which are also set on its spans
+func metricAttributes(call preset.CallInfo) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		OperationKey.String(call.Operation),
+		MethodKey.String(call.Method),
+	}
+}
+
+// Returns the attributes of the spans of a call
+func spanAttributes(call preset.CallInfo) []attribute.KeyValue {
+	attrs := metricAttributes(call)
+	if call.TeamID != 0 {
+		attrs = append(attrs, TeamIDKey.Int(call.TeamID))
+	}
+	if call.WorkspaceID != 0 {
+		attrs = append(attrs, WorkspaceIDKey.Int(call.WorkspaceID))
+	}
+
+	return attrs
+}
+
+// Records result on span, reporting whether it is a failure
+func endSpan(span trace.Span, result preset.CallResult) bool {
+	if result.StatusCode != 0 {
+		span.SetAttributes(StatusCodeKey.Int(result.StatusCode))
+	}
+
+	failed := result.Err != nil || result.StatusCode >= 400
+	switch {
+	case result.Err != nil:
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	case failed:
+		span.SetStatus(codes.Error, fmt.Sprintf("status %d", result.StatusCode))
+	}
+
+	return failed
+}
@@ -0,0 +1,170 @@
+package otelpreset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInstrumentation_SpansAndMetrics(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("Traceparent"))
+		if r.URL.Path == "/v1/teams/12/workspaces/34/memberships" {
+			// Simulate a 500 internal server error
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	instrumentation, err := New(
+		WithTracerProvider(tracerProvider),
+		WithMeterProvider(meterProvider),
+		WithPropagator(propagation.TraceContext{}),
+	)
+	assert.NoError(t, err)
+
+	client := &preset.PresetClient{
+		BaseURL:         mockServer.URL,
+		HTTPClient:      &http.Client{Timeout: 10 * time.Second},
+		Token:           "mockAccessToken",
+		Instrumentation: instrumentation,
+	}
+
+	_, err = client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	_, err = client.GetWorkspaceMembership(12, 34, nil)
+	assert.Error(t, err)
+
+	// Attempts end before the call they belong to
+	ended := spans.Ended()
+	assert.Len(t, ended, 4)
+
+	teamsAttempt, teams := ended[0], ended[1]
+	assert.Equal(t, "preset.GetAllTeams", teams.Name())
+	assert.Equal(t, trace.SpanKindInternal, teams.SpanKind())
+	assert.Equal(t, codes.Unset, teams.Status().Code)
+	assert.Equal(t, trace.SpanKindClient, teamsAttempt.SpanKind())
+	assert.Equal(t, teams.SpanContext().SpanID(), teamsAttempt.Parent().SpanID())
+
+	membershipAttempt, membership := ended[2], ended[3]
+	assert.Equal(t, "preset.GetWorkspaceMembership", membership.Name())
+	assert.Equal(t, codes.Error, membership.Status().Code)
+	assert.Equal(t, membership.SpanContext().SpanID(), membershipAttempt.Parent().SpanID())
+	attrs := attribute.NewSet(membership.Attributes()...)
+	teamID, _ := attrs.Value(TeamIDKey)
+	assert.Equal(t, int64(12), teamID.AsInt64())
+	workspaceID, _ := attrs.Value(WorkspaceIDKey)
+	assert.Equal(t, int64(34), workspaceID.AsInt64())
+	status, _ := attrs.Value(StatusCodeKey)
+	assert.Equal(t, int64(500), status.AsInt64())
+
+	attrs = attribute.NewSet(membershipAttempt.Attributes()...)
+	status, _ = attrs.Value(StatusCodeKey)
+	assert.Equal(t, int64(500), status.AsInt64())
+	attempt, _ := attrs.Value(AttemptKey)
+	assert.Equal(t, int64(1), attempt.AsInt64())
+
+	var metrics metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &metrics))
+
+	totals := map[string]int64{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					totals[m.Name] += point.Value
+					assertNoIDs(t, point.Attributes)
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					totals[m.Name] += int64(point.Count)
+					assertNoIDs(t, point.Attributes)
+				}
+			}
+		}
+	}
+	assert.Equal(t, int64(2), totals["preset.client.requests"])
+	assert.Equal(t, int64(1), totals["preset.client.errors"])
+	assert.Equal(t, int64(2), totals["preset.client.request.duration"])
+}
+
+// Asserts that metric attributes leave out team and workspace IDs, which would give a series
+// per team and workspace
+func assertNoIDs(t *testing.T, attrs attribute.Set) {
+	assert.False(t, attrs.HasValue(TeamIDKey))
+	assert.False(t, attrs.HasValue(WorkspaceIDKey))
+	assert.True(t, attrs.HasValue(OperationKey))
+}
+
+func TestInstrumentation_RetriesAreChildrenOfOneCall(t *testing.T) {
+	requests := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	instrumentation, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider()),
+		WithPropagator(propagation.TraceContext{}),
+	)
+	assert.NoError(t, err)
+
+	client := &preset.PresetClient{
+		BaseURL:         mockServer.URL,
+		HTTPClient:      &http.Client{Timeout: 10 * time.Second},
+		Token:           "mockAccessToken",
+		Instrumentation: instrumentation,
+		Retry:           &preset.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	_, err = client.GetAllWorkspaces(12, nil)
+	assert.NoError(t, err)
+
+	ended := spans.Ended()
+	assert.Len(t, ended, 4)
+
+	call := ended[3]
+	assert.Equal(t, "preset.GetAllWorkspaces", call.Name())
+	assert.Equal(t, codes.Unset, call.Status().Code)
+
+	for i, attempt := range ended[:3] {
+		assert.Equal(t, call.SpanContext().SpanID(), attempt.Parent().SpanID())
+		assert.Equal(t, call.SpanContext().TraceID(), attempt.SpanContext().TraceID())
+
+		attrs := attribute.NewSet(attempt.Attributes()...)
+		number, _ := attrs.Value(AttemptKey)
+		assert.Equal(t, int64(i+1), number.AsInt64())
+		_, resent := attrs.Value(ResendCountKey)
+		assert.Equal(t, i > 0, resent)
+	}
+}
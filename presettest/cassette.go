@@ -0,0 +1,538 @@
+package presettest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+// Mode selects what a Recorder does with requests
+type Mode int
+
+const (
+	// Serve responses from the cassette, failing requests it holds no interaction for
+	MODE_REPLAY Mode = iota
+	// Send requests to the live API and record the interactions, replacing the cassette on Save
+	MODE_RECORD
+	// Send requests to the live API and report responses whose schema differs from the cassette
+	MODE_VERIFY
+)
+
+// Returns the mode named by the PRESET_CASSETTE environment variable: record, verify or, by
+// default, replay
+func ModeFromEnv() Mode {
+	switch strings.ToLower(os.Getenv("PRESET_CASSETTE")) {
+	case "record":
+		return MODE_RECORD
+	case "verify":
+		return MODE_VERIFY
+	}
+	return MODE_REPLAY
+}
+
+// Cassette is the recorded interactions stored in a fixture file
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response it got
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// Drift is a difference between the schema of a live response and the recorded one
+type Drift struct {
+	Method string
+	Path   string
+	// Field paths such as payload[].user.email, with their JSON types where they differ
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+func (d Drift) String() string {
+	parts := []string{}
+	if len(d.Added) > 0 {
+		parts = append(parts, "added "+strings.Join(d.Added, ", "))
+	}
+	if len(d.Removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(d.Removed, ", "))
+	}
+	if len(d.Changed) > 0 {
+		parts = append(parts, "changed "+strings.Join(d.Changed, ", "))
+	}
+	return fmt.Sprintf("%s %s: %s", d.Method, d.Path, strings.Join(parts, "; "))
+}
+
+// Response headers kept in cassettes
+var keptHeaders = []string{"Content-Type", "Retry-After"}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// Placeholder written in place of scrubbed values
+const scrubbed = "REDACTED"
+
+// Boundary multipart bodies are re-encoded with, so that recorded requests match on replay
+const multipartBoundary = "presettest-boundary"
+
+// Recorder is an http.RoundTripper recording interactions with the live API to a cassette and
+// replaying them. Use it as the Transport of a PresetClient's HTTPClient.
+//
+// Cassettes are scrubbed as they are recorded: the values of secret JSON fields, form fields and
+// query parameters (see preset.IsSecretKey) are replaced with REDACTED, uploaded files with their
+// SHA-256 digest, and email addresses with a stable pseudonym at example.com. Requests are matched on method, path
+// and scrubbed body, so replayed tests must send the same requests as were recorded.
+type Recorder struct {
+	// Transport live requests are sent with; defaults to http.DefaultTransport
+	Transport http.RoundTripper
+	// Scrub, when set, further sanitizes each request and response body before it is stored
+	Scrub func(body []byte) []byte
+
+	path     string
+	mode     Mode
+	cassette Cassette
+	used     []bool
+
+	mu    sync.Mutex
+	drift []Drift
+}
+
+// Returns a Recorder for the cassette at path. Replaying and verifying need the cassette to exist.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, cassette: Cassette{Version: 1}}
+
+	if mode != MODE_RECORD {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// Returns an HTTP client sending requests through r
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Returns the schema differences found so far in MODE_VERIFY
+func (r *Recorder) Drift() []Drift {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Drift{}, r.drift...)
+}
+
+// Writes the recorded interactions to the cassette in MODE_RECORD, creating its directory
+func (r *Recorder) Save() error {
+	if r.mode != MODE_RECORD {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  scrubQuery(req.URL.RawQuery),
+		Body:   string(r.scrub(body, req.Header.Get("Content-Type"))),
+	}
+
+	if r.mode == MODE_REPLAY {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		interaction, ok := r.match(recorded)
+		if !ok {
+			return nil, fmt.Errorf("presettest: no interaction recorded in %s for %s %s with body %q", r.path, recorded.Method, recorded.Path, recorded.Body)
+		}
+		return interaction.Response.toResponse(req), nil
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	// The caller's request mustn't be modified, so the body that was read goes out on a clone
+	live := req.Clone(req.Context())
+	if body != nil {
+		live.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	res, err := transport.RoundTrip(live)
+	if err != nil {
+		return nil, err
+	}
+	res.Request = req
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	response := RecordedResponse{Status: res.StatusCode, Body: string(r.scrub(resBody, res.Header.Get("Content-Type")))}
+	for _, name := range keptHeaders {
+		if value := res.Header.Get(name); value != "" {
+			if response.Header == nil {
+				response.Header = map[string]string{}
+			}
+			response.Header[name] = value
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.mode {
+	case MODE_RECORD:
+		r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Request: recorded, Response: response})
+		r.used = append(r.used, true)
+	case MODE_VERIFY:
+		interaction, ok := r.match(recorded)
+		if !ok {
+			r.drift = append(r.drift, Drift{Method: recorded.Method, Path: recorded.Path, Added: []string{"(unrecorded request)"}})
+			break
+		}
+		if drift, ok := compareSchemas(interaction.Response.Body, response.Body); ok {
+			drift.Method = recorded.Method
+			drift.Path = recorded.Path
+			r.drift = append(r.drift, drift)
+		}
+	}
+
+	return res, nil
+}
+
+// Reads the body of req, if any, closing it as a RoundTripper must
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	return io.ReadAll(req.Body)
+}
+
+// Returns the first unused interaction matching req, or failing that the last used one, so
+// requests repeated more often than recorded keep getting the latest answer
+func (r *Recorder) match(req RecordedRequest) (Interaction, bool) {
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !interaction.Request.matches(req) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last >= 0 {
+		return r.cassette.Interactions[last], true
+	}
+	return Interaction{}, false
+}
+
+func (recorded RecordedRequest) matches(req RecordedRequest) bool {
+	return recorded.Method == req.Method && recorded.Path == req.Path && equalBodies(recorded.Body, req.Body)
+}
+
+// Compares bodies as JSON values when both parse, so key order and whitespace don't matter
+func equalBodies(a string, b string) bool {
+	var av, bv interface{}
+	if json.Unmarshal([]byte(a), &av) == nil && json.Unmarshal([]byte(b), &bv) == nil {
+		ab, _ := json.Marshal(av)
+		bb, _ := json.Marshal(bv)
+		return bytes.Equal(ab, bb)
+	}
+	return a == b
+}
+
+func (recorded RecordedResponse) toResponse(req *http.Request) *http.Response {
+	header := http.Header{}
+	for name, value := range recorded.Header {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
+
+// Returns body with secrets and email addresses replaced
+func (r *Recorder) scrub(body []byte, contentType string) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	if form, ok := scrubMultipart(body, contentType); ok {
+		body = form
+	} else {
+		body = scrubJSON(body)
+	}
+	body = emailPattern.ReplaceAllFunc(body, pseudonymizeEmail)
+
+	if r.Scrub != nil {
+		body = r.Scrub(body)
+	}
+	return body
+}
+
+// Returns a query string with the values of secret parameters replaced and email addresses
+// pseudonymized
+func scrubQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	// A malformed query still yields the parameters that could be parsed
+	values, _ := url.ParseQuery(rawQuery)
+	for key, items := range values {
+		for i, item := range items {
+			if preset.IsSecretKey(key) {
+				items[i] = scrubbed
+				continue
+			}
+			items[i] = emailPattern.ReplaceAllStringFunc(item, func(email string) string {
+				return string(pseudonymizeEmail([]byte(email)))
+			})
+		}
+	}
+	return values.Encode()
+}
+
+// Returns body with the values of secret keys replaced, if it is JSON
+func scrubJSON(body []byte) []byte {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	scrubbedBody, _ := json.Marshal(scrubValue(value))
+	return scrubbedBody
+}
+
+func scrubValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if preset.IsSecretKey(key) {
+				// Whatever its type, e.g. a map of passwords by database
+				v[key] = scrubbed
+				continue
+			}
+			v[key] = scrubValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = scrubValue(item)
+		}
+	}
+	return value
+}
+
+// Re-encodes a multipart body with a fixed boundary, replacing secret fields and file contents.
+// Returns false if body isn't multipart.
+func scrubMultipart(body []byte, contentType string) ([]byte, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, false
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	writer.SetBoundary(multipartBoundary)
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, false
+		}
+
+		switch {
+		case preset.IsSecretKey(part.FormName()):
+			content = []byte(scrubbed)
+		case part.FileName() != "":
+			sum := sha256.Sum256(content)
+			content = []byte("sha256:" + hex.EncodeToString(sum[:]))
+		default:
+			content = scrubJSON(content)
+		}
+
+		w, err := writer.CreatePart(part.Header)
+		if err != nil {
+			return nil, false
+		}
+		w.Write(content)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, false
+	}
+	return form.Bytes(), true
+}
+
+// Replaces an email address with a pseudonym derived from it, so the same address always
+// scrubs to the same one and recordings stay consistent across requests
+func pseudonymizeEmail(email []byte) []byte {
+	if bytes.HasSuffix(email, []byte("@example.com")) {
+		return email
+	}
+	sum := sha256.Sum256(bytes.ToLower(email))
+	return []byte("user-" + hex.EncodeToString(sum[:4]) + "@example.com")
+}
+
+// Compares the JSON schemas of the recorded and live bodies. Returns false if they match.
+func compareSchemas(recorded string, live string) (Drift, bool) {
+	recordedSchema, errRecorded := schemaOf(recorded)
+	liveSchema, errLive := schemaOf(live)
+	if errRecorded != nil && errLive != nil {
+		return Drift{}, false
+	}
+	if errRecorded != nil || errLive != nil {
+		return Drift{Changed: []string{"(body is no longer JSON, or only now is)"}}, true
+	}
+
+	drift := Drift{}
+	for path, kind := range liveSchema {
+		recordedKind, ok := recordedSchema[path]
+		switch {
+		case !ok:
+			drift.Added = append(drift.Added, path)
+		case recordedKind != kind && recordedKind != "null" && kind != "null":
+			drift.Changed = append(drift.Changed, fmt.Sprintf("%s (%s -> %s)", path, recordedKind, kind))
+		}
+	}
+	for path := range recordedSchema {
+		if _, ok := liveSchema[path]; !ok {
+			drift.Removed = append(drift.Removed, path)
+		}
+	}
+
+	if len(drift.Added)+len(drift.Removed)+len(drift.Changed) == 0 {
+		return Drift{}, false
+	}
+	sort.Strings(drift.Added)
+	sort.Strings(drift.Removed)
+	sort.Strings(drift.Changed)
+	return drift, true
+}
+
+// Returns the JSON type of every field path in body. Array elements share the path of their
+// array followed by [].
+func schemaOf(body string) (map[string]string, error) {
+	if body == "" {
+		return map[string]string{}, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return nil, err
+	}
+
+	schema := map[string]string{}
+	collectSchema(value, "", schema)
+	return schema, nil
+}
+
+func collectSchema(value interface{}, path string, schema map[string]string) {
+	kind := "null"
+	switch v := value.(type) {
+	case map[string]interface{}:
+		kind = "object"
+		for key, item := range v {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			collectSchema(item, child, schema)
+		}
+	case []interface{}:
+		kind = "array"
+		for _, item := range v {
+			collectSchema(item, path+"[]", schema)
+		}
+	case string:
+		kind = "string"
+	case float64:
+		kind = "number"
+	case bool:
+		kind = "boolean"
+	}
+
+	if path == "" {
+		return
+	}
+	// An element that is null doesn't override the type seen in another
+	if existing, ok := schema[path]; ok && kind == "null" && existing != "null" {
+		return
+	}
+	schema[path] = kind
+}
@@ -0,0 +1,210 @@
+package presettest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	srv := NewServer(t)
+	srv.AddAPIKey("name", "topsecret")
+	team := srv.AddTeam(preset.Team{Name: "acme"})
+	srv.AddTeamMember(team.ID, preset.User{Email: "ada@corp.io"}, preset.TEAM_USER)
+
+	path := filepath.Join(t.TempDir(), "cassettes", "memberships.json")
+
+	// Record against the fake as if it were the live API
+	recorder, err := NewRecorder(path, MODE_RECORD)
+	assert.NoError(t, err)
+	client := &preset.PresetClient{BaseURL: srv.URL, HTTPClient: recorder.Client(), Auth: preset.AuthStruct{TokenName: "name", Secret: "topsecret"}}
+
+	_, err = client.GetAccessToken()
+	assert.NoError(t, err)
+	recorded, err := client.GetTeamMembership(team.ID, 0, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "topsecret")
+	assert.NotContains(t, string(data), "presettest-token")
+	assert.NotContains(t, string(data), "ada@corp.io")
+
+	// Replay without the server
+	srv.Close()
+	replayer, err := NewRecorder(path, MODE_REPLAY)
+	assert.NoError(t, err)
+	client = &preset.PresetClient{BaseURL: srv.URL, HTTPClient: replayer.Client(), Auth: preset.AuthStruct{TokenName: "name", Secret: "other"}}
+
+	ar, err := client.GetAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "REDACTED", ar.Payload.AccessToken)
+
+	replayed, err := client.GetTeamMembership(team.ID, 0, nil)
+	assert.NoError(t, err)
+	assert.Len(t, *replayed, 1)
+	assert.Equal(t, (*recorded)[0].User.ID, (*replayed)[0].User.ID)
+	assert.Equal(t, pseudonymizeEmail([]byte("ada@corp.io")), []byte((*replayed)[0].User.Email))
+
+	// Requests that weren't recorded fail
+	_, err = client.UpdateUserTeamRole(team.ID, 1, preset.TEAM_ADMIN, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no interaction recorded")
+	assert.Contains(t, err.Error(), "PUT /v1/team/")
+}
+
+func TestRecorder_DetectsDrift(t *testing.T) {
+	payload := `{"payload": [{"id": 1, "name": "acme", "tier": "PROFESSIONAL"}]}`
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(payload))
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "teams.json")
+	recorder, err := NewRecorder(path, MODE_RECORD)
+	assert.NoError(t, err)
+	client := &preset.PresetClient{BaseURL: mockServer.URL, HTTPClient: recorder.Client()}
+	_, err = client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.Save())
+
+	// Same schema, different values
+	payload = `{"payload": [{"id": 2, "name": "other", "tier": "STARTER"}]}`
+	verifier, err := NewRecorder(path, MODE_VERIFY)
+	assert.NoError(t, err)
+	client.HTTPClient = verifier.Client()
+	_, err = client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, verifier.Drift())
+
+	payload = `{"payload": [{"id": "1", "title": "acme", "tier": "PROFESSIONAL"}]}`
+	verifier, err = NewRecorder(path, MODE_VERIFY)
+	assert.NoError(t, err)
+	client.HTTPClient = verifier.Client()
+	_, err = client.GetAllTeams(nil)
+	assert.Error(t, err)
+
+	drift := verifier.Drift()
+	assert.Len(t, drift, 1)
+	assert.Equal(t, []string{"payload[].title"}, drift[0].Added)
+	assert.Equal(t, []string{"payload[].name"}, drift[0].Removed)
+	assert.Equal(t, []string{"payload[].id (number -> string)"}, drift[0].Changed)
+	assert.Contains(t, drift[0].String(), "GET /v1/teams")
+}
+
+func TestRecorder_MissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), MODE_REPLAY)
+	assert.Error(t, err)
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv("PRESET_CASSETTE", "record")
+	assert.Equal(t, MODE_RECORD, ModeFromEnv())
+	t.Setenv("PRESET_CASSETTE", "")
+	assert.Equal(t, MODE_REPLAY, ModeFromEnv())
+}
+
+// Builds a multipart form like the one asset imports send, with a random boundary
+func importForm(t *testing.T) (*bytes.Buffer, string) {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	file, err := writer.CreateFormFile("formData", "bundle.zip")
+	assert.NoError(t, err)
+	file.Write([]byte("zip bytes"))
+	assert.NoError(t, writer.WriteField("overwrite", "true"))
+	assert.NoError(t, writer.WriteField("passwords", `{"databases/warehouse.yaml": "hunter2"}`))
+	assert.NoError(t, writer.Close())
+	return &form, writer.FormDataContentType()
+}
+
+func TestRecorder_ScrubsSecretsOfAnyType(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result": {"guest_token": {"value": "gt-123"}, "encrypted_extra": {"key": "k-456"}, "api_key": 789}}`))
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "import.json")
+	recorder, err := NewRecorder(path, MODE_RECORD)
+	assert.NoError(t, err)
+
+	res, err := recorder.Client().Post(mockServer.URL+"/api/v1/database/", "application/json",
+		strings.NewReader(`{"passwords": {"databases/warehouse.yaml": "hunter2"}, "ssh_tunnel_passwords": ["tunnel-pw"]}`))
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	form, contentType := importForm(t)
+	res, err = recorder.Client().Post(mockServer.URL+"/api/v1/assets/import/", contentType, form)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	for _, secret := range []string{"hunter2", "tunnel-pw", "gt-123", "k-456", "789", "zip bytes"} {
+		assert.NotContains(t, string(data), secret)
+	}
+	assert.Contains(t, string(data), "overwrite")
+
+	// The same form sent with another boundary matches the recording
+	replayer, err := NewRecorder(path, MODE_REPLAY)
+	assert.NoError(t, err)
+	form, contentType = importForm(t)
+	res, err = replayer.Client().Post(mockServer.URL+"/api/v1/assets/import/", contentType, form)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestRecorder_ScrubsQueriesWithoutTouchingTheRequest(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"name": "acme"}`, string(body))
+		assert.Equal(t, "secret-token", r.URL.Query().Get("token"))
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.json")
+	recorder, err := NewRecorder(path, MODE_RECORD)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", mockServer.URL+"/v1/teams/1/audit-logs?token=secret-token&api_key=k-1&actor=jane.doe%40acme.io&page=2", strings.NewReader(`{"name": "acme"}`))
+	assert.NoError(t, err)
+	body := req.Body
+	res, err := recorder.RoundTrip(req)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.True(t, body == req.Body)
+	assert.Equal(t, req, res.Request)
+	assert.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	for _, secret := range []string{"secret-token", "k-1", "jane.doe", "acme.io"} {
+		assert.NotContains(t, string(data), secret)
+	}
+
+	cassette := Cassette{}
+	assert.NoError(t, json.Unmarshal(data, &cassette))
+	query, err := url.ParseQuery(cassette.Interactions[0].Request.Query)
+	assert.NoError(t, err)
+	assert.Equal(t, "REDACTED", query.Get("token"))
+	assert.Equal(t, "REDACTED", query.Get("api_key"))
+	assert.Equal(t, "2", query.Get("page"))
+	assert.True(t, strings.HasSuffix(query.Get("actor"), "@example.com"))
+}
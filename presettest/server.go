@@ -0,0 +1,833 @@
+// Package presettest provides an in-process fake of the Preset manager API for tests.
+//
+// The fake keeps teams, workspaces and memberships in memory, so a test can seed data, exercise
+// code against a real *preset.PresetClient and then inspect the resulting state:
+//
+//	srv := presettest.NewServer(t)
+//	team := srv.AddTeam(preset.Team{Name: "acme"})
+//	srv.AddTeamMember(team.ID, preset.User{Email: "ada@example.com"}, preset.TEAM_USER)
+//	srv.Inject(presettest.Fault{Path: "/v1/teams", Status: http.StatusTooManyRequests, Times: 1})
+//
+//	client := srv.Client(t)
+//	...
+//	calls := srv.Calls()
+//
+// For contract tests against the live API, Recorder records interactions to scrubbed cassette
+// files once and replays them in CI, reporting schema drift when run in MODE_VERIFY.
+package presettest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+// Team roles as named by the manager API
+var teamRoleNames = map[preset.TeamRoleEnum]string{
+	preset.TEAM_ADMIN: "Admin",
+	preset.TEAM_USER:  "User",
+}
+
+// Workspace roles as named by the manager API, keyed by role identifier
+var workspaceRoleNames = map[string]string{
+	"Admin":                "Workspace Admin",
+	"PresetAlpha":          "Primary Contributor",
+	"PresetBeta":           "Secondary Contributor",
+	"PresetGamma":          "Limited Contributor",
+	"PresetReportsOnly":    "Viewer",
+	"PresetDashboardsOnly": "Dashboard Viewer",
+	"PresetNoAccess":       "No Access",
+}
+
+// Call is a request received by the Server
+type Call struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// Status the Server responded with
+	Status int
+}
+
+// Fault makes the Server misbehave on matching requests
+type Fault struct {
+	// Method to match; empty matches any
+	Method string
+	// Path to match, as a path.Match pattern such as /v1/teams/*/workspaces; empty matches any
+	Path string
+	// Delay before responding
+	Latency time.Duration
+	// Status to respond with instead of handling the request; 0 handles it normally after Latency
+	Status int
+	// Body to respond with along with Status
+	Body string
+	// Retry-After header to send along with Status, in seconds
+	RetryAfter int
+	// Number of requests to affect; 0 affects all of them
+	Times int
+}
+
+// Returns whether f applies to a request
+func (f *Fault) matches(method string, requestPath string) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, method) {
+		return false
+	}
+	if f.Path != "" {
+		if ok, _ := path.Match(f.Path, requestPath); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Server is a stateful fake of the Preset manager API. It is safe for concurrent use.
+type Server struct {
+	// URL of the fake, to be used as a PresetClient's BaseURL
+	URL string
+	// When set, requests other than auth must carry a token issued by the fake in their
+	// Authorization header, which PresetClient sends when given an authToken
+	RequireAuth bool
+
+	server *httptest.Server
+
+	mu               sync.Mutex
+	nextID           int
+	apiKeys          map[string]string
+	tokens           map[string]bool
+	teams            []*preset.Team
+	workspaces       map[int][]*preset.Workspace
+	teamMembers      map[int][]*preset.TeamMembership
+	workspaceMembers map[int][]*preset.WorkspaceMembership
+	invites          map[int][]preset.TeamInvite
+	faults           []*Fault
+	calls            []Call
+}
+
+// Starts a Server, which is closed when the test finishes
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		nextID:           1,
+		apiKeys:          map[string]string{},
+		tokens:           map[string]bool{},
+		workspaces:       map[int][]*preset.Workspace{},
+		teamMembers:      map[int][]*preset.TeamMembership{},
+		workspaceMembers: map[int][]*preset.WorkspaceMembership{},
+		invites:          map[int][]preset.TeamInvite{},
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Shuts the Server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Returns a client of the Server, authenticated with an API key added for it. Fails the test if
+// authenticating fails.
+func (s *Server) Client(tb testing.TB) *preset.PresetClient {
+	tb.Helper()
+	s.AddAPIKey("presettest", "presettest")
+
+	client := &preset.PresetClient{
+		BaseURL:    s.URL,
+		HTTPClient: s.server.Client(),
+		Auth:       preset.AuthStruct{TokenName: "presettest", Secret: "presettest"},
+	}
+	ar, err := client.GetAccessToken()
+	if err != nil {
+		tb.Fatalf("presettest: authenticating client: %v", err)
+		return nil
+	}
+	client.Token = ar.Payload.AccessToken
+
+	return client
+}
+
+// Lets the API key name and secret exchange for access tokens
+func (s *Server) AddAPIKey(name string, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apiKeys[name] = secret
+}
+
+// Issues an access token without going through auth, e.g. to pass as authToken
+func (s *Server) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.issueToken()
+}
+
+func (s *Server) issueToken() string {
+	token := fmt.Sprintf("presettest-token-%d", s.newID())
+	s.tokens[token] = true
+	return token
+}
+
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// Adds a team, assigning it an ID unless it has one, and returns it
+func (s *Server) AddTeam(team preset.Team) preset.Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if team.ID == 0 {
+		team.ID = s.newID()
+	}
+	if team.Name == "" {
+		team.Name = fmt.Sprintf("team-%d", team.ID)
+	}
+	if team.Title == "" {
+		team.Title = team.Name
+	}
+	s.teams = append(s.teams, &team)
+
+	return team
+}
+
+// Adds a workspace to a team, assigning it an ID and hostname unless it has them, and returns it
+func (s *Server) AddWorkspace(teamID int, workspace preset.Workspace) preset.Workspace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if workspace.ID == 0 {
+		workspace.ID = s.newID()
+	}
+	if workspace.Name == "" {
+		workspace.Name = fmt.Sprintf("workspace-%d", workspace.ID)
+	}
+	if workspace.Title == "" {
+		workspace.Title = workspace.Name
+	}
+	if workspace.Hostname == "" {
+		workspace.Hostname = workspace.Name + ".presettest.local"
+	}
+	if workspace.WorkspaceStatus == "" {
+		workspace.WorkspaceStatus = preset.WORKSPACE_READY
+	}
+	workspace.TeamID = teamID
+	s.workspaces[teamID] = append(s.workspaces[teamID], &workspace)
+	if team := s.team(teamID); team != nil {
+		team.WorkspaceCount = len(s.workspaces[teamID])
+	}
+
+	return workspace
+}
+
+// Adds a user to a team with the given role, assigning the user an ID unless they have one
+func (s *Server) AddTeamMember(teamID int, user preset.User, role preset.TeamRoleEnum) preset.TeamMembership {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == 0 {
+		user.ID = s.newID()
+	}
+	membership := &preset.TeamMembership{
+		TeamRole: preset.TeamRole{ID: int(role), Name: teamRoleNames[role]},
+		User:     user,
+	}
+	s.teamMembers[teamID] = append(s.teamMembers[teamID], membership)
+	if team := s.team(teamID); team != nil {
+		team.UserCount = len(s.teamMembers[teamID])
+	}
+
+	return *membership
+}
+
+// Gives a user a role in a workspace, identified as in the API, e.g. PresetAlpha
+func (s *Server) AddWorkspaceMember(workspaceID int, user preset.User, roleIdentifier string) preset.WorkspaceMembership {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == 0 {
+		user.ID = s.newID()
+	}
+	membership := s.setWorkspaceRole(workspaceID, user, roleIdentifier)
+
+	return *membership
+}
+
+func (s *Server) setWorkspaceRole(workspaceID int, user preset.User, roleIdentifier string) *preset.WorkspaceMembership {
+	role := preset.WorkspaceRole{Name: workspaceRoleNames[roleIdentifier], RoleIdentifier: roleIdentifier}
+	for _, membership := range s.workspaceMembers[workspaceID] {
+		if membership.User.ID == user.ID {
+			membership.WorkspaceRole = role
+			return membership
+		}
+	}
+
+	membership := &preset.WorkspaceMembership{User: user, WorkspaceRole: role}
+	s.workspaceMembers[workspaceID] = append(s.workspaceMembers[workspaceID], membership)
+	return membership
+}
+
+// Returns the teams held by the Server
+func (s *Server) Teams() []preset.Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	teams := []preset.Team{}
+	for _, team := range s.teams {
+		teams = append(teams, *team)
+	}
+	return teams
+}
+
+// Returns the workspaces of a team
+func (s *Server) Workspaces(teamID int) []preset.Workspace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workspaces := []preset.Workspace{}
+	for _, workspace := range s.workspaces[teamID] {
+		workspaces = append(workspaces, *workspace)
+	}
+	return workspaces
+}
+
+// Returns the members of a team
+func (s *Server) TeamMembers(teamID int) []preset.TeamMembership {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	memberships := []preset.TeamMembership{}
+	for _, membership := range s.teamMembers[teamID] {
+		memberships = append(memberships, *membership)
+	}
+	return memberships
+}
+
+// Returns the members of a workspace
+func (s *Server) WorkspaceMembers(workspaceID int) []preset.WorkspaceMembership {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	memberships := []preset.WorkspaceMembership{}
+	for _, membership := range s.workspaceMembers[workspaceID] {
+		memberships = append(memberships, *membership)
+	}
+	return memberships
+}
+
+// Returns the invites sent to a team
+func (s *Server) Invites(teamID int) []preset.TeamInvite {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]preset.TeamInvite{}, s.invites[teamID]...)
+}
+
+// Makes the Server misbehave on matching requests. Faults apply in the order they were injected.
+func (s *Server) Inject(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &fault)
+}
+
+// Removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Returns the requests received so far, oldest first
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call{}, s.calls...)
+}
+
+// Returns the requests received so far with the given method and path
+func (s *Server) CallsTo(method string, requestPath string) []Call {
+	calls := []Call{}
+	for _, call := range s.Calls() {
+		if call.Method == method && call.Path == requestPath {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Forgets the requests received so far
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = nil
+}
+
+// Records the status a request was answered with
+type recorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.calls = append(s.calls, Call{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+			Status: rec.status,
+		})
+	}()
+
+	if fault := s.takeFault(r); fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status != 0 {
+			if fault.RetryAfter > 0 {
+				rec.Header().Set("Retry-After", strconv.Itoa(fault.RetryAfter))
+			}
+			rec.WriteHeader(fault.Status)
+			io.WriteString(rec, fault.Body)
+			return
+		}
+	}
+
+	s.route(rec, r)
+}
+
+// Returns the first fault matching r, using up one of its Times
+func (s *Server) takeFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, fault := range s.faults {
+		if !fault.matches(r.Method, r.URL.Path) {
+			continue
+		}
+
+		taken := *fault
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &taken
+	}
+	return nil
+}
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handle  func(s *Server, w http.ResponseWriter, r *http.Request, ids []int)
+}
+
+var routes = []route{
+	{"POST", regexp.MustCompile(`^/v1/auth$`), (*Server).handleAuth},
+	{"GET", regexp.MustCompile(`^/v1/teams$`), (*Server).handleGetTeams},
+	{"PATCH", regexp.MustCompile(`^/v1/teams/(\d+)$`), (*Server).handleUpdateTeam},
+	{"GET", regexp.MustCompile(`^/v1/team/(\d+)/memberships$`), (*Server).handleGetTeamMembers},
+	{"PUT", regexp.MustCompile(`^/v1/team/(\d+)/memberships/(\d+)$`), (*Server).handleUpdateTeamMember},
+	{"DELETE", regexp.MustCompile(`^/v1/team/(\d+)/memberships/(\d+)$`), (*Server).handleDeleteTeamMember},
+	{"GET", regexp.MustCompile(`^/v1/teams/(\d+)/workspaces$`), (*Server).handleGetWorkspaces},
+	{"POST", regexp.MustCompile(`^/v1/teams/(\d+)/workspaces$`), (*Server).handleCreateWorkspace},
+	{"DELETE", regexp.MustCompile(`^/v1/teams/(\d+)/workspaces/(\d+)$`), (*Server).handleDeleteWorkspace},
+	{"PATCH", regexp.MustCompile(`^/v1/teams/(\d+)/workspaces/(\d+)$`), (*Server).handleUpdateWorkspace},
+	{"GET", regexp.MustCompile(`^/v1/teams/(\d+)/workspaces/(\d+)/memberships$`), (*Server).handleGetWorkspaceMembers},
+	{"PUT", regexp.MustCompile(`^/v1/team/(\d+)/workspaces/(\d+)/membership$`), (*Server).handleUpdateWorkspaceMember},
+	{"POST", regexp.MustCompile(`^/v1/teams/(\d+)/invites/many$`), (*Server).handleSendInvites},
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	for _, rt := range routes {
+		match := rt.pattern.FindStringSubmatch(r.URL.Path)
+		if match == nil || rt.method != r.Method {
+			continue
+		}
+
+		if rt.method != "POST" || r.URL.Path != "/v1/auth" {
+			if !s.authorized(r) {
+				writeError(w, http.StatusUnauthorized, "invalid or missing access token")
+				return
+			}
+		}
+
+		ids := []int{}
+		for _, group := range match[1:] {
+			id, _ := strconv.Atoi(group)
+			ids = append(ids, id)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		rt.handle(s, w, r, ids)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "not found")
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if !s.RequireAuth {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return s.tokens[token]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"errors": []map[string]string{{"message": message}}})
+}
+
+func writePayload(w http.ResponseWriter, payload interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"payload": payload})
+}
+
+func (s *Server) team(teamID int) *preset.Team {
+	for _, team := range s.teams {
+		if team.ID == teamID {
+			return team
+		}
+	}
+	return nil
+}
+
+func (s *Server) workspace(teamID int, workspaceID int) *preset.Workspace {
+	for _, workspace := range s.workspaces[teamID] {
+		if workspace.ID == workspaceID {
+			return workspace
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request, _ []int) {
+	auth := preset.AuthStruct{}
+	if err := json.NewDecoder(r.Body).Decode(&auth); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	secret, ok := s.apiKeys[auth.TokenName]
+	if !ok || secret != auth.Secret {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+
+	writePayload(w, preset.AuthPayload{AccessToken: s.issueToken()})
+}
+
+func (s *Server) handleGetTeams(w http.ResponseWriter, _ *http.Request, _ []int) {
+	teams := []preset.Team{}
+	for _, team := range s.teams {
+		teams = append(teams, *team)
+	}
+	writePayload(w, teams)
+}
+
+func (s *Server) handleUpdateTeam(w http.ResponseWriter, r *http.Request, ids []int) {
+	team := s.team(ids[0])
+	if team == nil {
+		writeError(w, http.StatusNotFound, "team not found")
+		return
+	}
+
+	update := struct {
+		FeatureFlags map[string]interface{} `json:"feature_flags"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Merge the flags through JSON so modeled and unmodeled ones are both kept
+	flags := map[string]interface{}{}
+	current, _ := json.Marshal(team.FeatureFlags)
+	json.Unmarshal(current, &flags)
+	for name, value := range update.FeatureFlags {
+		flags[name] = value
+	}
+	merged, _ := json.Marshal(flags)
+	team.FeatureFlags = preset.Flags{}
+	json.Unmarshal(merged, &team.FeatureFlags)
+
+	writePayload(w, team)
+}
+
+func (s *Server) handleGetTeamMembers(w http.ResponseWriter, _ *http.Request, ids []int) {
+	if s.team(ids[0]) == nil {
+		writeError(w, http.StatusNotFound, "team not found")
+		return
+	}
+
+	memberships := []preset.TeamMembership{}
+	for _, membership := range s.teamMembers[ids[0]] {
+		memberships = append(memberships, *membership)
+	}
+	writePayload(w, memberships)
+}
+
+func (s *Server) handleUpdateTeamMember(w http.ResponseWriter, r *http.Request, ids []int) {
+	update := struct {
+		TeamRoleID preset.TeamRoleEnum `json:"team_role_id"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name, ok := teamRoleNames[update.TeamRoleID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid team role")
+		return
+	}
+
+	for _, membership := range s.teamMembers[ids[0]] {
+		if membership.User.ID == ids[1] {
+			membership.TeamRole = preset.TeamRole{ID: int(update.TeamRoleID), Name: name}
+			writePayload(w, membership)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "membership not found")
+}
+
+func (s *Server) handleDeleteTeamMember(w http.ResponseWriter, _ *http.Request, ids []int) {
+	members := s.teamMembers[ids[0]]
+	for i, membership := range members {
+		if membership.User.ID != ids[1] {
+			continue
+		}
+
+		s.teamMembers[ids[0]] = append(members[:i:i], members[i+1:]...)
+		if team := s.team(ids[0]); team != nil {
+			team.UserCount = len(s.teamMembers[ids[0]])
+		}
+		// Removing someone from the team removes them from its workspaces too
+		for _, workspace := range s.workspaces[ids[0]] {
+			s.removeWorkspaceMember(workspace.ID, ids[1])
+		}
+
+		writePayload(w, map[string]interface{}{})
+		return
+	}
+	writeError(w, http.StatusNotFound, "membership not found")
+}
+
+func (s *Server) removeWorkspaceMember(workspaceID int, userID int) {
+	members := s.workspaceMembers[workspaceID]
+	for i, membership := range members {
+		if membership.User.ID == userID {
+			s.workspaceMembers[workspaceID] = append(members[:i:i], members[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Server) handleGetWorkspaces(w http.ResponseWriter, _ *http.Request, ids []int) {
+	if s.team(ids[0]) == nil {
+		writeError(w, http.StatusNotFound, "team not found")
+		return
+	}
+
+	workspaces := []preset.Workspace{}
+	for _, workspace := range s.workspaces[ids[0]] {
+		workspaces = append(workspaces, *workspace)
+	}
+	writePayload(w, workspaces)
+}
+
+func (s *Server) handleCreateWorkspace(w http.ResponseWriter, r *http.Request, ids []int) {
+	team := s.team(ids[0])
+	if team == nil {
+		writeError(w, http.StatusNotFound, "team not found")
+		return
+	}
+
+	payload := preset.WorkspacePayload{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Title == "" {
+		writeError(w, http.StatusBadRequest, "missing workspace title")
+		return
+	}
+
+	id := s.newID()
+	workspace := &preset.Workspace{
+		ID:              id,
+		Name:            fmt.Sprintf("workspace-%d", id),
+		Title:           payload.Title,
+		Descr:           payload.Descr,
+		Region:          payload.Region,
+		Hostname:        fmt.Sprintf("workspace-%d.presettest.local", id),
+		TeamID:          team.ID,
+		WorkspaceStatus: preset.WORKSPACE_PENDING,
+	}
+	s.workspaces[team.ID] = append(s.workspaces[team.ID], workspace)
+	team.WorkspaceCount = len(s.workspaces[team.ID])
+
+	writePayload(w, workspace)
+}
+
+func (s *Server) handleDeleteWorkspace(w http.ResponseWriter, _ *http.Request, ids []int) {
+	workspaces := s.workspaces[ids[0]]
+	for i, workspace := range workspaces {
+		if workspace.ID != ids[1] {
+			continue
+		}
+
+		s.workspaces[ids[0]] = append(workspaces[:i:i], workspaces[i+1:]...)
+		delete(s.workspaceMembers, ids[1])
+		if team := s.team(ids[0]); team != nil {
+			team.WorkspaceCount = len(s.workspaces[ids[0]])
+		}
+
+		writePayload(w, map[string]interface{}{})
+		return
+	}
+	writeError(w, http.StatusNotFound, "workspace not found")
+}
+
+func (s *Server) handleUpdateWorkspace(w http.ResponseWriter, r *http.Request, ids []int) {
+	workspace := s.workspace(ids[0], ids[1])
+	if workspace == nil {
+		writeError(w, http.StatusNotFound, "workspace not found")
+		return
+	}
+
+	settings := preset.WorkspaceSettings{}
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if settings.AiAssistActivated != nil {
+		workspace.AiAssistActivated = *settings.AiAssistActivated
+	}
+	if settings.AllowPublicDashboards != nil {
+		workspace.AllowPublicDashboards = *settings.AllowPublicDashboards
+	}
+
+	writePayload(w, workspace)
+}
+
+func (s *Server) handleGetWorkspaceMembers(w http.ResponseWriter, _ *http.Request, ids []int) {
+	if s.workspace(ids[0], ids[1]) == nil {
+		writeError(w, http.StatusNotFound, "workspace not found")
+		return
+	}
+
+	memberships := []preset.WorkspaceMembership{}
+	for _, membership := range s.workspaceMembers[ids[1]] {
+		memberships = append(memberships, *membership)
+	}
+	writePayload(w, memberships)
+}
+
+func (s *Server) handleUpdateWorkspaceMember(w http.ResponseWriter, r *http.Request, ids []int) {
+	if s.workspace(ids[0], ids[1]) == nil {
+		writeError(w, http.StatusNotFound, "workspace not found")
+		return
+	}
+
+	update := struct {
+		UserID         int    `json:"user_id"`
+		RoleIdentifier string `json:"role_identifier"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, ok := workspaceRoleNames[update.RoleIdentifier]; !ok {
+		writeError(w, http.StatusBadRequest, "invalid role identifier")
+		return
+	}
+
+	// Only team members can be given a workspace role
+	for _, membership := range s.teamMembers[ids[0]] {
+		if membership.User.ID == update.UserID {
+			writePayload(w, s.setWorkspaceRole(ids[1], membership.User, update.RoleIdentifier))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "user is not a member of the team")
+}
+
+func (s *Server) handleSendInvites(w http.ResponseWriter, r *http.Request, ids []int) {
+	if s.team(ids[0]) == nil {
+		writeError(w, http.StatusNotFound, "team not found")
+		return
+	}
+
+	request := struct {
+		Invites []preset.Invite `json:"invites"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sent := []preset.TeamInvite{}
+	for _, invite := range request.Invites {
+		if _, ok := teamRoleNames[invite.TeamRoleID]; !ok || invite.Email == "" {
+			writeError(w, http.StatusBadRequest, "invalid invite")
+			return
+		}
+		sent = append(sent, preset.TeamInvite{
+			ID:             s.newID(),
+			Email:          invite.Email,
+			TeamID:         ids[0],
+			TeamRoleID:     invite.TeamRoleID,
+			ExpirationDate: preset.Timestamp{Time: time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)},
+		})
+	}
+	s.invites[ids[0]] = append(s.invites[ids[0]], sent...)
+
+	writePayload(w, sent)
+}
@@ -0,0 +1,185 @@
+package presettest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	preset "github.com/vadivelselvaraj/preset-sdk-go"
+)
+
+func TestServer_TeamsAndWorkspaces(t *testing.T) {
+	srv := NewServer(t)
+	team := srv.AddTeam(preset.Team{Name: "acme"})
+	workspace := srv.AddWorkspace(team.ID, preset.Workspace{Name: "analytics"})
+
+	client := srv.Client(t)
+
+	teams, err := client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.Len(t, *teams, 1)
+	assert.Equal(t, "acme", (*teams)[0].Name)
+	assert.Equal(t, 1, (*teams)[0].WorkspaceCount)
+
+	workspaces, err := client.GetAllWorkspaces(team.ID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, *workspaces, 1)
+	assert.Equal(t, workspace.ID, (*workspaces)[0].ID)
+	assert.Equal(t, preset.WORKSPACE_READY, (*workspaces)[0].WorkspaceStatus)
+
+	enabled := true
+	updated, err := client.UpdateWorkspaceSettings(team.ID, workspace.ID, preset.WorkspaceSettings{AllowPublicDashboards: &enabled}, nil)
+	assert.NoError(t, err)
+	assert.True(t, updated.AllowPublicDashboards)
+	assert.True(t, srv.Workspaces(team.ID)[0].AllowPublicDashboards)
+
+	flags, err := client.UpdateTeamFeatureFlags(team.ID, map[string]interface{}{"alert_reports": true, "new_flag": true}, nil)
+	assert.NoError(t, err)
+	assert.True(t, flags.AlertReports)
+	assert.True(t, flags.Enabled("new_flag"))
+
+	_, err = client.GetAllWorkspaces(999, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status: 404")
+}
+
+func TestServer_Memberships(t *testing.T) {
+	srv := NewServer(t)
+	team := srv.AddTeam(preset.Team{Name: "acme"})
+	workspace := srv.AddWorkspace(team.ID, preset.Workspace{})
+	member := srv.AddTeamMember(team.ID, preset.User{Email: "ada@example.com"}, preset.TEAM_USER)
+	client := srv.Client(t)
+
+	membership, err := client.UpdateUserTeamRole(team.ID, member.User.ID, preset.TEAM_ADMIN, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Admin", membership.TeamRole.Name)
+
+	workspaceMembership, err := client.UpdateUserWorkspaceRole(team.ID, workspace.ID, member.User.ID, "viewer", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "PresetReportsOnly", workspaceMembership.WorkspaceRole.RoleIdentifier)
+	assert.Equal(t, "ada@example.com", workspaceMembership.User.Email)
+
+	memberships, err := client.GetWorkspaceMembership(team.ID, workspace.ID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, *memberships, 1)
+
+	// Users outside the team can't be given a workspace role
+	_, err = client.UpdateUserWorkspaceRole(team.ID, workspace.ID, 999, "viewer", nil)
+	assert.Error(t, err)
+
+	assert.NoError(t, client.DeleteTeamMembership(team.ID, member.User.ID, nil))
+	assert.Empty(t, srv.TeamMembers(team.ID))
+	assert.Empty(t, srv.WorkspaceMembers(workspace.ID))
+}
+
+func TestServer_RequireAuth(t *testing.T) {
+	srv := NewServer(t)
+	srv.RequireAuth = true
+	srv.AddTeam(preset.Team{})
+	client := srv.Client(t)
+
+	_, err := client.GetAllTeams(nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status: 401")
+
+	token := "Bearer " + client.Token
+	teams, err := client.GetAllTeams(&token)
+	assert.NoError(t, err)
+	assert.Len(t, *teams, 1)
+
+	client.Auth.Secret = "wrong"
+	_, err = client.GetAccessToken()
+	assert.Error(t, err)
+}
+
+func TestServer_Faults(t *testing.T) {
+	srv := NewServer(t)
+	team := srv.AddTeam(preset.Team{})
+	client := srv.Client(t)
+	client.Retry = &preset.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	srv.ResetCalls()
+
+	srv.Inject(Fault{Method: "GET", Path: "/v1/teams", Status: http.StatusTooManyRequests, RetryAfter: 1, Times: 1})
+	srv.Inject(Fault{Path: "/v1/teams/*/workspaces", Status: http.StatusInternalServerError, Body: `{"message": "boom"}`})
+
+	start := time.Now()
+	_, err := client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	_, err = client.GetAllWorkspaces(team.ID, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+
+	calls := srv.CallsTo("GET", "/v1/teams")
+	assert.Len(t, calls, 2)
+	assert.Equal(t, http.StatusTooManyRequests, calls[0].Status)
+	assert.Equal(t, http.StatusOK, calls[1].Status)
+
+	srv.ClearFaults()
+	srv.Inject(Fault{Latency: 50 * time.Millisecond})
+	start = time.Now()
+	_, err = client.GetAllWorkspaces(team.ID, nil)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestServer_RecordsCalls(t *testing.T) {
+	srv := NewServer(t)
+	team := srv.AddTeam(preset.Team{})
+	member := srv.AddTeamMember(team.ID, preset.User{}, preset.TEAM_USER)
+	client := srv.Client(t)
+
+	_, err := client.UpdateUserTeamRole(team.ID, member.User.ID, preset.TEAM_ADMIN, nil)
+	assert.NoError(t, err)
+
+	calls := srv.Calls()
+	assert.Equal(t, "/v1/auth", calls[0].Path)
+	last := calls[len(calls)-1]
+	assert.Equal(t, "PUT", last.Method)
+	assert.JSONEq(t, `{"team_role_id": 1}`, string(last.Body))
+}
+
+func TestServer_CreateDeleteWorkspaceAndInvites(t *testing.T) {
+	srv := NewServer(t)
+	team := srv.AddTeam(preset.Team{})
+	client := srv.Client(t)
+
+	workspace, err := client.CreateWorkspace(team.ID, preset.WorkspacePayload{Title: "Analytics"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, preset.WORKSPACE_PENDING, workspace.WorkspaceStatus)
+	assert.Len(t, srv.Workspaces(team.ID), 1)
+
+	assert.NoError(t, client.DeleteWorkspace(team.ID, workspace.ID, nil))
+	assert.Empty(t, srv.Workspaces(team.ID))
+	assert.Error(t, client.DeleteWorkspace(team.ID, workspace.ID, nil))
+
+	invites, err := client.SendInvites(team.ID, []preset.Invite{{Email: "ada@example.com", TeamRoleID: preset.TEAM_USER}}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, *invites, 1)
+	assert.False(t, (*invites)[0].ExpirationDate.IsZero())
+	assert.Equal(t, "ada@example.com", srv.Invites(team.ID)[0].Email)
+}
+
+// Records the failure instead of stopping the test
+type fatalRecorder struct {
+	testing.TB
+	failure string
+}
+
+func (f *fatalRecorder) Helper() {}
+
+func (f *fatalRecorder) Fatalf(format string, args ...interface{}) {
+	f.failure = fmt.Sprintf(format, args...)
+}
+
+func TestServer_ClientFailsTestWhenAuthFails(t *testing.T) {
+	srv := NewServer(t)
+	srv.Inject(Fault{Method: "POST", Path: "/v1/auth", Status: http.StatusInternalServerError})
+
+	tb := &fatalRecorder{TB: t}
+	assert.Nil(t, srv.Client(tb))
+	assert.Contains(t, tb.failure, "authenticating client")
+}
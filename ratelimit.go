@@ -0,0 +1,95 @@
+package preset
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces requests. *rate.Limiter from golang.org/x/time/rate is a RateLimiter.
+type RateLimiter interface {
+	// Blocks until a request may be sent, or returns an error if ctx is done first
+	Wait(ctx context.Context) error
+}
+
+// Returns a Middleware waiting on limiter before sending each request
+func RateLimitMiddleware(limiter RateLimiter) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			err := limiter.Wait(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// TokenBucket is a RateLimiter allowing a steady rate of requests with bursts
+type TokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+}
+
+// Returns a TokenBucket allowing perSecond requests per second on average, and up to burst at once.
+// A perSecond of 0 or less doesn't limit requests at all.
+func NewTokenBucket(perSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:     perSecond,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// Takes a token, waiting for one to be available if necessary
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		delay := b.take()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Takes a token if one is available, otherwise returns how long until one is
+func (b *TokenBucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Without a rate the bucket would never refill
+	if b.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.lastFill).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.lastFill = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	if b.rate <= 0 {
+		return time.Second
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
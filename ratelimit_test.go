@@ -0,0 +1,69 @@
+package preset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Paces(t *testing.T) {
+	bucket := NewTokenBucket(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, bucket.Wait(ctx))
+	}
+	// Two requests fit in the burst, the other two wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestTokenBucket_Cancel(t *testing.T) {
+	bucket := NewTokenBucket(0.001, 1)
+	assert.NoError(t, bucket.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bucket.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestTokenBucket_NoRateIsUnlimited(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, rate := range []float64{0, -1} {
+		bucket := NewTokenBucket(rate, 1)
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, bucket.Wait(ctx))
+		}
+	}
+}
+
+func TestRateLimit_Client(t *testing.T) {
+	calls := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"payload": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:     mockServer.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		Token:       "mockAccessToken",
+		RateLimiter: NewTokenBucket(50, 1),
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.GetAllTeams(nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 3, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
@@ -0,0 +1,153 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ReportType distinguishes alerts, which fire on a SQL condition, from scheduled reports
+type ReportType string
+
+const (
+	REPORT_ALERT  ReportType = "Alert"
+	REPORT_REPORT ReportType = "Report"
+)
+
+// RecipientType is the channel a report is delivered to
+type RecipientType string
+
+const (
+	RECIPIENT_EMAIL RecipientType = "Email"
+	RECIPIENT_SLACK RecipientType = "Slack"
+)
+
+// ValidatorType is how an alert's SQL result is checked
+type ValidatorType string
+
+const (
+	VALIDATOR_NOT_NULL ValidatorType = "not null"
+	VALIDATOR_OPERATOR ValidatorType = "operator"
+)
+
+// ReportState is the outcome of a report schedule execution
+type ReportState string
+
+const (
+	REPORT_STATE_SUCCESS       ReportState = "Success"
+	REPORT_STATE_WORKING       ReportState = "Working"
+	REPORT_STATE_ERROR         ReportState = "Error"
+	REPORT_STATE_NOT_TRIGGERED ReportState = "Not triggered"
+	REPORT_STATE_GRACE         ReportState = "On Grace"
+)
+
+// Returns all alerts and reports of the workspace
+func (s *SupersetClient) GetAllReportSchedules(ctx context.Context) (*[]ReportSchedule, error) {
+	reports, err := getAllPages[ReportSchedule](ctx, s, "GetAllReportSchedules", "/api/v1/report/")
+	if err != nil {
+		return nil, err
+	}
+
+	return &reports, nil
+}
+
+// Returns a single alert or report
+func (s *SupersetClient) GetReportSchedule(ctx context.Context, reportID int) (*ReportSchedule, error) {
+	req, err := s.newRequest(ctx, "GetReportSchedule", "GET", fmt.Sprintf("/api/v1/report/%d", reportID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rsr := ReportScheduleResponse{}
+	err = s.doDecode(req, &rsr)
+	if err != nil {
+		return nil, err
+	}
+
+	report := rsr.Result
+	return &report, nil
+}
+
+// Creates an alert or report and returns its ID
+func (s *SupersetClient) CreateReportSchedule(ctx context.Context, payload ReportSchedulePayload) (int, error) {
+	if payload.Type != nil && *payload.Type != REPORT_ALERT && *payload.Type != REPORT_REPORT {
+		return 0, fmt.Errorf("invalid report type")
+	}
+
+	req, err := s.newRequest(ctx, "CreateReportSchedule", "POST", "/api/v1/report/", payload)
+	if err != nil {
+		return 0, err
+	}
+
+	rsr := ReportScheduleResponse{}
+	err = s.doDecode(req, &rsr)
+	if err != nil {
+		return 0, err
+	}
+
+	return rsr.ID, nil
+}
+
+// Updates the fields set in payload on an existing alert or report
+func (s *SupersetClient) UpdateReportSchedule(ctx context.Context, reportID int, payload ReportSchedulePayload) error {
+	if payload.Type != nil && *payload.Type != REPORT_ALERT && *payload.Type != REPORT_REPORT {
+		return fmt.Errorf("invalid report type")
+	}
+
+	req, err := s.newRequest(ctx, "UpdateReportSchedule", "PUT", fmt.Sprintf("/api/v1/report/%d", reportID), payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Deletes an alert or report
+func (s *SupersetClient) DeleteReportSchedule(ctx context.Context, reportID int) error {
+	req, err := s.newRequest(ctx, "DeleteReportSchedule", "DELETE", fmt.Sprintf("/api/v1/report/%d", reportID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Returns the execution history of an alert or report
+func (s *SupersetClient) ListReportExecutionLogs(ctx context.Context, reportID int) (*[]ReportExecutionLog, error) {
+	logs, err := getAllPages[ReportExecutionLog](ctx, s, "ListReportExecutionLogs", fmt.Sprintf("/api/v1/report/%d/log/", reportID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &logs, nil
+}
+
+// Superset accepts validator and recipient configs as objects on writes, but its GET endpoints
+// return them as JSON-encoded strings. The configs decode from either form and always encode
+// as objects.
+
+func (c *ValidatorConfig) UnmarshalJSON(data []byte) error {
+	type validatorConfig ValidatorConfig
+	return unmarshalStringOrObject(data, (*validatorConfig)(c))
+}
+
+func (c *ReportRecipientConfig) UnmarshalJSON(data []byte) error {
+	type reportRecipientConfig ReportRecipientConfig
+	return unmarshalStringOrObject(data, (*reportRecipientConfig)(c))
+}
+
+// Decodes data into v, first unwrapping it if it is a JSON document encoded as a string.
+// An empty string leaves v as it is.
+func unmarshalStringOrObject(data []byte, v interface{}) error {
+	var encoded string
+	if json.Unmarshal(data, &encoded) == nil {
+		if encoded == "" {
+			return nil
+		}
+		data = []byte(encoded)
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,215 @@
+package preset
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReportSchedule_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/report/4", r.URL.Path)
+		response := []byte(`{
+			"id": 4,
+			"result": {
+				"id": 4,
+				"type": "Alert",
+				"name": "Revenue drop",
+				"active": true,
+				"crontab": "0 * * * *",
+				"timezone": "America/New_York",
+				"recipients": [
+					{"type": "Email", "recipient_config_json": {"target": "finance@example.com"}},
+					{"type": "Slack", "recipient_config_json": {"target": "#finance-alerts"}}
+				],
+				"chart": {"id": 21, "slice_name": "Revenue"},
+				"dashboard": null,
+				"database": {"id": 1, "database_name": "warehouse"},
+				"sql": "SELECT SUM(revenue) FROM sales WHERE ds = CURRENT_DATE",
+				"validator_type": "operator",
+				"validator_config_json": {"op": "<", "threshold": 1000},
+				"working_timeout": 3600,
+				"grace_period": 14400,
+				"last_state": "Error",
+				"last_eval_dttm": "2023-08-01T12:00:00.123456"
+			}
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	report, err := superset.GetReportSchedule(context.Background(), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, REPORT_ALERT, report.Type)
+	assert.Equal(t, RECIPIENT_SLACK, report.Recipients[1].Type)
+	assert.Equal(t, "#finance-alerts", report.Recipients[1].RecipientConfigJSON.Target)
+	assert.Equal(t, 21, report.Chart.ID)
+	assert.Nil(t, report.Dashboard)
+	assert.Equal(t, VALIDATOR_OPERATOR, report.ValidatorType)
+	assert.Equal(t, float64(1000), report.ValidatorConfigJSON.Threshold)
+	assert.Equal(t, 14400, report.GracePeriod)
+	assert.Equal(t, REPORT_STATE_ERROR, report.LastState)
+	assert.True(t, time.Date(2023, 8, 1, 12, 0, 0, 123456000, time.UTC).Equal(report.LastEvalDttm.Time))
+}
+
+func TestGetAllReportSchedules_StringEncodedConfigs(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate the list response of a real server, which encodes the configs as strings
+		assert.Equal(t, "/api/v1/report/", r.URL.Path)
+		response := []byte(`{
+			"count": 2,
+			"result": [
+				{
+					"id": 4,
+					"type": "Alert",
+					"name": "Revenue drop",
+					"recipients": [{"type": "Email", "recipient_config_json": "{\"target\": \"finance@example.com\"}"}],
+					"validator_type": "operator",
+					"validator_config_json": "{\"op\": \"<\", \"threshold\": 1000}"
+				},
+				{
+					"id": 5,
+					"type": "Report",
+					"name": "Weekly sales",
+					"recipients": [{"type": "Slack", "recipient_config_json": "{\"target\": \"#sales\"}"}],
+					"validator_config_json": ""
+				}
+			]
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	reports, err := superset.GetAllReportSchedules(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, *reports, 2)
+	assert.Equal(t, "finance@example.com", (*reports)[0].Recipients[0].RecipientConfigJSON.Target)
+	assert.Equal(t, "<", (*reports)[0].ValidatorConfigJSON.Op)
+	assert.Equal(t, float64(1000), (*reports)[0].ValidatorConfigJSON.Threshold)
+	assert.Equal(t, "#sales", (*reports)[1].Recipients[0].RecipientConfigJSON.Target)
+	assert.Equal(t, float64(0), (*reports)[1].ValidatorConfigJSON.Threshold)
+}
+
+func TestCreateReportSchedule_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{
+			"type": "Report",
+			"name": "Weekly sales",
+			"crontab": "0 9 * * 1",
+			"recipients": [{"type": "Email", "recipient_config_json": {"target": "sales@example.com"}}],
+			"dashboard": 7
+		}`, string(body))
+
+		// Simulate a 201 created response
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 5, "result": {}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	reportType := REPORT_REPORT
+	name := "Weekly sales"
+	crontab := "0 9 * * 1"
+	recipients := []ReportRecipient{{Type: RECIPIENT_EMAIL, RecipientConfigJSON: ReportRecipientConfig{Target: "sales@example.com"}}}
+	dashboard := 7
+	id, err := superset.CreateReportSchedule(context.Background(), ReportSchedulePayload{
+		Type:       &reportType,
+		Name:       &name,
+		Crontab:    &crontab,
+		Recipients: &recipients,
+		Dashboard:  &dashboard,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, id)
+}
+
+func TestCreateReportSchedule_InvalidType(t *testing.T) {
+	superset := &SupersetClient{BaseURL: "mockBaseURL"}
+
+	reportType := ReportType("Digest")
+	_, err := superset.CreateReportSchedule(context.Background(), ReportSchedulePayload{Type: &reportType})
+	assert.EqualError(t, err, "invalid report type")
+}
+
+func TestListReportExecutionLogs_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/report/4/log/", r.URL.Path)
+		response := []byte(`{
+			"count": 2,
+			"result": [
+				{"id": 1, "state": "Success", "scheduled_dttm": "2023-08-01T09:00:00", "value": 1250.5},
+				{"id": 2, "state": "Error", "scheduled_dttm": "2023-08-01T10:00:00", "value": null, "error_message": "Alert query returned more than one row"}
+			]
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	logs, err := superset.ListReportExecutionLogs(context.Background(), 4)
+	assert.NoError(t, err)
+	assert.Len(t, *logs, 2)
+	assert.Equal(t, 1250.5, *(*logs)[0].Value)
+	assert.Equal(t, REPORT_STATE_ERROR, (*logs)[1].State)
+	assert.Nil(t, (*logs)[1].Value)
+}
+
+func TestDeleteReportSchedule_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	err := superset.DeleteReportSchedule(context.Background(), 4)
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package preset
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests failing transiently are resent. Requests are retried on
+// transport errors and on 429, 502, 503 and 504 responses; all but 429 are only retried for
+// idempotent methods unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// Total number of attempts, including the first
+	MaxAttempts int
+	// Delay before the first retry, doubled for each following one with jitter
+	BaseDelay time.Duration
+	// Upper bound on the delay between attempts, including one asked for by Retry-After
+	MaxDelay time.Duration
+	// Retries POST and PATCH requests as well
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts, waiting 500ms and then about 1s between them
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// Returns a Middleware resending requests according to policy. Each attempt's request context
+// records its attempt number, which logging and instrumentation report.
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+
+			for attempt := 1; ; attempt++ {
+				// Each attempt gets its own headers, so middlewares further in start afresh
+				attemptReq := req.Clone(withAttempt(ctx, attempt))
+				if attempt > 1 && req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					attemptReq.Body = body
+				}
+
+				res, err := next.Do(attemptReq)
+				if attempt >= policy.MaxAttempts || !policy.retryable(req, res, err) {
+					return res, err
+				}
+
+				delay := policy.backoff(attempt, res)
+				if res != nil {
+					io.Copy(io.Discard, res.Body)
+					res.Body.Close()
+				}
+
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				case <-timer.C:
+				}
+			}
+		})
+	}
+}
+
+// Reports whether the outcome of an attempt at req warrants another
+func (p RetryPolicy) retryable(req *http.Request, res *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	idempotent := p.RetryNonIdempotent
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		idempotent = true
+	}
+
+	if err != nil {
+		return idempotent
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// Returns how long to wait after the given attempt, honouring any Retry-After in res
+func (p RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	limit := p.MaxDelay
+	if limit <= 0 {
+		limit = math.MaxInt64 / 2
+	}
+
+	// Doubling stops at the limit, so that many attempts neither overflow nor go negative
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay > 0 && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+	if delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	} else {
+		delay = 0
+	}
+
+	if res != nil {
+		if after, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			delay = after
+		}
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// Parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
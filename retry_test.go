@@ -0,0 +1,134 @@
+package preset
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry_ResendsBody(t *testing.T) {
+	bodies := []string{}
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"payload": {"access_token": "mockAccessToken"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Auth:       AuthStruct{TokenName: "name", Secret: "secret"},
+		Retry:      &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour},
+	}
+
+	_, err := client.GetAccessToken()
+	assert.NoError(t, err)
+	assert.Len(t, bodies, 3)
+	assert.Equal(t, bodies[0], bodies[2])
+	assert.Contains(t, bodies[2], `"secret":"secret"`)
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	calls := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// Simulate a 503 service unavailable error
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+		Retry:      &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+	}
+
+	_, err := client.GetAllTeams(nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status: 503")
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetry_SkipsNonIdempotent(t *testing.T) {
+	calls := 0
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// Simulate a 503 service unavailable error
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Auth:       AuthStruct{TokenName: "name", Secret: "secret"},
+		Retry:      &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	_, err := client.GetAccessToken()
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_StopsOnCancel(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer mockServer.Close()
+
+	doer := RetryMiddleware(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour})(&http.Client{Timeout: 10 * time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", mockServer.URL, nil)
+
+	_, err := doer.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	delay, ok := parseRetryAfter("2")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+
+	delay, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestRetry_BackoffStaysBounded(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 200, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+	for _, attempt := range []int{1, 10, 40, 64, 100, 200} {
+		delay := policy.backoff(attempt, nil)
+		assert.True(t, delay > 0 && delay <= 30*time.Second, "attempt %d waited %s", attempt, delay)
+	}
+
+	// Without MaxDelay, delays grow without overflowing
+	policy.MaxDelay = 0
+	for _, attempt := range []int{64, 100, 200} {
+		assert.True(t, policy.backoff(attempt, nil) > 0)
+	}
+
+	// A non-positive BaseDelay retries immediately
+	policy.BaseDelay = -time.Second
+	assert.Equal(t, time.Duration(0), policy.backoff(3, nil))
+}
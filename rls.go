@@ -0,0 +1,140 @@
+package preset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RLSFilterType controls whether an RLS clause applies to the listed roles or to everyone else
+type RLSFilterType string
+
+const (
+	// The clause applies to the roles of the filter
+	RLS_REGULAR RLSFilterType = "Regular"
+	// The clause applies to every role except those of the filter
+	RLS_BASE RLSFilterType = "Base"
+)
+
+// Returns all row-level security filters of the workspace
+func (s *SupersetClient) GetAllRLSFilters(ctx context.Context) (*[]RLSFilter, error) {
+	filters, err := getAllPages[RLSFilter](ctx, s, "GetAllRLSFilters", "/api/v1/rowlevelsecurity/")
+	if err != nil {
+		return nil, err
+	}
+
+	return &filters, nil
+}
+
+// Returns a single row-level security filter
+func (s *SupersetClient) GetRLSFilter(ctx context.Context, filterID int) (*RLSFilter, error) {
+	req, err := s.newRequest(ctx, "GetRLSFilter", "GET", fmt.Sprintf("/api/v1/rowlevelsecurity/%d", filterID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rr := RLSFilterResponse{}
+	err = s.doDecode(req, &rr)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := rr.Result
+	return &filter, nil
+}
+
+// Creates a row-level security filter and returns its ID
+func (s *SupersetClient) CreateRLSFilter(ctx context.Context, payload RLSFilterPayload) (int, error) {
+	if payload.FilterType != nil && *payload.FilterType != RLS_REGULAR && *payload.FilterType != RLS_BASE {
+		return 0, fmt.Errorf("invalid filter type")
+	}
+
+	req, err := s.newRequest(ctx, "CreateRLSFilter", "POST", "/api/v1/rowlevelsecurity/", payload)
+	if err != nil {
+		return 0, err
+	}
+
+	rmr := RLSFilterMutationResponse{}
+	err = s.doDecode(req, &rmr)
+	if err != nil {
+		return 0, err
+	}
+
+	return rmr.ID, nil
+}
+
+// Updates the fields set in payload on an existing row-level security filter
+func (s *SupersetClient) UpdateRLSFilter(ctx context.Context, filterID int, payload RLSFilterPayload) error {
+	if payload.FilterType != nil && *payload.FilterType != RLS_REGULAR && *payload.FilterType != RLS_BASE {
+		return fmt.Errorf("invalid filter type")
+	}
+
+	req, err := s.newRequest(ctx, "UpdateRLSFilter", "PUT", fmt.Sprintf("/api/v1/rowlevelsecurity/%d", filterID), payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Deletes a row-level security filter
+func (s *SupersetClient) DeleteRLSFilter(ctx context.Context, filterID int) error {
+	req, err := s.newRequest(ctx, "DeleteRLSFilter", "DELETE", fmt.Sprintf("/api/v1/rowlevelsecurity/%d", filterID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Checks that every dataset ID and role name exists in the workspace before a filter referencing
+// them is submitted, and returns the IDs of the roles in the order of roleNames
+func (s *SupersetClient) ValidateRLSReferences(ctx context.Context, datasetIDs []int, roleNames []string) ([]int, error) {
+	tables, err := getAllPages[RelatedItem](ctx, s, "ValidateRLSReferences", "/api/v1/rowlevelsecurity/related/tables")
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := getAllPages[RelatedItem](ctx, s, "ValidateRLSReferences", "/api/v1/rowlevelsecurity/related/roles")
+	if err != nil {
+		return nil, err
+	}
+
+	knownTables := map[int]bool{}
+	for _, table := range tables {
+		knownTables[table.Value] = true
+	}
+
+	roleIDs := map[string]int{}
+	for _, role := range roles {
+		roleIDs[role.Text] = role.Value
+	}
+
+	missing := []string{}
+	for _, id := range datasetIDs {
+		if !knownTables[id] {
+			missing = append(missing, "dataset "+strconv.Itoa(id))
+		}
+	}
+
+	ids := []int{}
+	for _, name := range roleNames {
+		id, found := roleIDs[name]
+		if !found {
+			missing = append(missing, fmt.Sprintf("role %q", name))
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("unknown references: %s", strings.Join(missing, ", "))
+	}
+
+	return ids, nil
+}
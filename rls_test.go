@@ -0,0 +1,114 @@
+package preset
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRLSFilter_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/rowlevelsecurity/3", r.URL.Path)
+		response := []byte(`{
+			"id": 3,
+			"result": {
+				"id": 3,
+				"name": "Tenant isolation",
+				"filter_type": "Regular",
+				"tables": [{"id": 12, "schema": "public", "table_name": "orders"}],
+				"roles": [{"id": 4, "name": "tenant_acme"}],
+				"group_key": "tenant",
+				"clause": "tenant_id = 42"
+			}
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	filter, err := superset.GetRLSFilter(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, RLS_REGULAR, filter.FilterType)
+	assert.Equal(t, "orders", filter.Tables[0].TableName)
+	assert.Equal(t, "tenant_acme", filter.Roles[0].Name)
+	assert.Equal(t, "tenant_id = 42", filter.Clause)
+}
+
+func TestCreateRLSFilter_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name": "Tenant isolation", "filter_type": "Base", "tables": [12], "roles": [4], "clause": "1 = 0"}`, string(body))
+
+		// Simulate a 201 created response
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 8, "result": {}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	name := "Tenant isolation"
+	filterType := RLS_BASE
+	tables := []int{12}
+	roles := []int{4}
+	clause := "1 = 0"
+	id, err := superset.CreateRLSFilter(context.Background(), RLSFilterPayload{Name: &name, FilterType: &filterType, Tables: &tables, Roles: &roles, Clause: &clause})
+	assert.NoError(t, err)
+	assert.Equal(t, 8, id)
+}
+
+func TestCreateRLSFilter_InvalidFilterType(t *testing.T) {
+	superset := &SupersetClient{BaseURL: "mockBaseURL"}
+
+	filterType := RLSFilterType("Other")
+	_, err := superset.CreateRLSFilter(context.Background(), RLSFilterPayload{FilterType: &filterType})
+	assert.EqualError(t, err, "invalid filter type")
+}
+
+func TestValidateRLSReferences(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case "/api/v1/rowlevelsecurity/related/tables":
+			w.Write([]byte(`{"count": 2, "result": [{"value": 12, "text": "public.orders"}, {"value": 13, "text": "public.customers"}]}`))
+		case "/api/v1/rowlevelsecurity/related/roles":
+			w.Write([]byte(`{"count": 2, "result": [{"value": 4, "text": "tenant_acme"}, {"value": 5, "text": "tenant_globex"}]}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	roleIDs, err := superset.ValidateRLSReferences(context.Background(), []int{12, 13}, []string{"tenant_globex", "tenant_acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 4}, roleIDs)
+
+	_, err = superset.ValidateRLSReferences(context.Background(), []int{12, 99}, []string{"tenant_initech"})
+	assert.EqualError(t, err, `unknown references: dataset 99, role "tenant_initech"`)
+}
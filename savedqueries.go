@@ -0,0 +1,76 @@
+package preset
+
+import (
+	"context"
+	"fmt"
+)
+
+// Returns all saved queries of the workspace
+func (s *SupersetClient) GetAllSavedQueries(ctx context.Context) (*[]SavedQuery, error) {
+	queries, err := getAllPages[SavedQuery](ctx, s, "GetAllSavedQueries", "/api/v1/saved_query/")
+	if err != nil {
+		return nil, err
+	}
+
+	return &queries, nil
+}
+
+// Returns a single saved query
+func (s *SupersetClient) GetSavedQuery(ctx context.Context, savedQueryID int) (*SavedQuery, error) {
+	req, err := s.newRequest(ctx, "GetSavedQuery", "GET", fmt.Sprintf("/api/v1/saved_query/%d", savedQueryID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sqr := SavedQueryResponse{}
+	err = s.doDecode(req, &sqr)
+	if err != nil {
+		return nil, err
+	}
+
+	query := sqr.Result
+	return &query, nil
+}
+
+// Creates a saved query and returns its ID
+func (s *SupersetClient) CreateSavedQuery(ctx context.Context, payload SavedQueryPayload) (int, error) {
+	req, err := s.newRequest(ctx, "CreateSavedQuery", "POST", "/api/v1/saved_query/", payload)
+	if err != nil {
+		return 0, err
+	}
+
+	sqr := SavedQueryResponse{}
+	err = s.doDecode(req, &sqr)
+	if err != nil {
+		return 0, err
+	}
+
+	return sqr.ID, nil
+}
+
+// Updates the fields set in payload on an existing saved query
+func (s *SupersetClient) UpdateSavedQuery(ctx context.Context, savedQueryID int, payload SavedQueryPayload) error {
+	req, err := s.newRequest(ctx, "UpdateSavedQuery", "PUT", fmt.Sprintf("/api/v1/saved_query/%d", savedQueryID), payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Deletes a saved query
+func (s *SupersetClient) DeleteSavedQuery(ctx context.Context, savedQueryID int) error {
+	req, err := s.newRequest(ctx, "DeleteSavedQuery", "DELETE", fmt.Sprintf("/api/v1/saved_query/%d", savedQueryID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Exports the given saved queries, with the databases they run against, as an in-memory bundle
+func (s *SupersetClient) ExportSavedQueries(ctx context.Context, savedQueryIDs []int) (*AssetBundle, error) {
+	return s.ExportAssets(ctx, ASSET_SAVED_QUERY, savedQueryIDs)
+}
@@ -0,0 +1,134 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllSavedQueries_Paginates(t *testing.T) {
+	pages := []string{}
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		pages = append(pages, q)
+
+		// Simulate a first full page followed by a last page holding a single query
+		result := []SavedQuery{}
+		if q == "(page:0,page_size:100)" {
+			for i := 0; i < 100; i++ {
+				result = append(result, SavedQuery{ID: i + 1})
+			}
+		} else {
+			result = append(result, SavedQuery{ID: 101, Label: "Revenue by region"})
+		}
+
+		response, _ := json.Marshal(map[string]interface{}{"count": 101, "result": result})
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	queries, err := superset.GetAllSavedQueries(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, *queries, 101)
+	assert.Equal(t, "Revenue by region", (*queries)[100].Label)
+	assert.Equal(t, []string{"(page:0,page_size:100)", "(page:1,page_size:100)"}, pages)
+}
+
+func TestGetSavedQuery_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/saved_query/5", r.URL.Path)
+		response := []byte(`{
+			"id": 5,
+			"result": {
+				"id": 5,
+				"label": "Revenue by region",
+				"description": "Canonical revenue query",
+				"schema": "public",
+				"sql": "SELECT region, SUM(revenue) FROM sales GROUP BY 1",
+				"database": {"id": 1, "database_name": "warehouse"}
+			}
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	query, err := superset.GetSavedQuery(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "Revenue by region", query.Label)
+	assert.Equal(t, "public", query.Schema)
+	assert.Equal(t, 1, query.Database.ID)
+}
+
+func TestCreateSavedQuery_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"label": "Revenue", "db_id": 1, "sql": "SELECT 1"}`, string(body))
+
+		// Simulate a 201 created response
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 6, "result": {"label": "Revenue", "db_id": 1, "sql": "SELECT 1"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	label := "Revenue"
+	databaseID := 1
+	sql := "SELECT 1"
+	id, err := superset.CreateSavedQuery(context.Background(), SavedQueryPayload{Label: &label, DatabaseID: &databaseID, SQL: &sql})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, id)
+}
+
+func TestUpdateSavedQuery_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	sql := "SELECT 2"
+	err := superset.UpdateSavedQuery(context.Background(), 5, SavedQueryPayload{SQL: &sql})
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package preset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Returns all security roles of the workspace
+func (s *SupersetClient) ListRoles(ctx context.Context) (*[]SecurityRole, error) {
+	roles, err := getAllPages[SecurityRole](ctx, s, "ListRoles", "/api/v1/security/roles/")
+	if err != nil {
+		return nil, err
+	}
+
+	return &roles, nil
+}
+
+// Creates a security role and returns its ID
+func (s *SupersetClient) CreateRole(ctx context.Context, name string) (int, error) {
+	payload := map[string]interface{}{
+		"name": name,
+	}
+
+	req, err := s.newRequest(ctx, "CreateRole", "POST", "/api/v1/security/roles/", payload)
+	if err != nil {
+		return 0, err
+	}
+
+	srr := SecurityRoleResponse{}
+	err = s.doDecode(req, &srr)
+	if err != nil {
+		return 0, err
+	}
+
+	return srr.ID, nil
+}
+
+// Renames a security role
+func (s *SupersetClient) UpdateRole(ctx context.Context, roleID int, name string) error {
+	payload := map[string]interface{}{
+		"name": name,
+	}
+
+	req, err := s.newRequest(ctx, "UpdateRole", "PUT", fmt.Sprintf("/api/v1/security/roles/%d", roleID), payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Deletes a security role
+func (s *SupersetClient) DeleteRole(ctx context.Context, roleID int) error {
+	req, err := s.newRequest(ctx, "DeleteRole", "DELETE", fmt.Sprintf("/api/v1/security/roles/%d", roleID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Returns every permission and view menu pair that can be granted to a role
+func (s *SupersetClient) ListPermissions(ctx context.Context) (*[]PermissionViewMenu, error) {
+	permissions, err := getAllPages[PermissionViewMenu](ctx, s, "ListPermissions", "/api/v1/security/permissions-resources/")
+	if err != nil {
+		return nil, err
+	}
+
+	return &permissions, nil
+}
+
+// Replaces the permissions of a role with the given pairs. Every pair must already exist in the workspace.
+func (s *SupersetClient) SetRolePermissions(ctx context.Context, roleID int, permissions []PermissionView) error {
+	available, err := s.ListPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := map[PermissionView]int{}
+	for _, pvm := range *available {
+		ids[PermissionView{Permission: pvm.Permission.Name, ViewMenu: pvm.ViewMenu.Name}] = pvm.ID
+	}
+
+	pvmIDs := []int{}
+	missing := []string{}
+	for _, permission := range permissions {
+		id, found := ids[permission]
+		if !found {
+			missing = append(missing, permission.String())
+			continue
+		}
+		pvmIDs = append(pvmIDs, id)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("unknown permissions: %s", strings.Join(missing, ", "))
+	}
+
+	payload := map[string]interface{}{
+		"permission_view_menu_ids": pvmIDs,
+	}
+
+	req, err := s.newRequest(ctx, "SetRolePermissions", "POST", fmt.Sprintf("/api/v1/security/roles/%d/permissions", roleID), payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+func (p PermissionView) String() string {
+	return fmt.Sprintf("%s on %s", p.Permission, p.ViewMenu)
+}
+
+// Grants access to every database
+func AllDatabaseAccess() PermissionView {
+	return PermissionView{Permission: "all_database_access", ViewMenu: "all_database_access"}
+}
+
+// Grants access to every dataset
+func AllDatasourceAccess() PermissionView {
+	return PermissionView{Permission: "all_datasource_access", ViewMenu: "all_datasource_access"}
+}
+
+// Grants access to a database and everything in it
+func DatabaseAccess(database string, databaseID int) PermissionView {
+	return PermissionView{Permission: "database_access", ViewMenu: fmt.Sprintf("[%s].(id:%d)", database, databaseID)}
+}
+
+// Grants access to every dataset in a schema of a database
+func SchemaAccess(database string, schema string) PermissionView {
+	return PermissionView{Permission: "schema_access", ViewMenu: fmt.Sprintf("[%s].[%s]", database, schema)}
+}
+
+// Grants access to a single dataset. Superset names dataset permissions after the database,
+// table and dataset ID; the schema is not part of it.
+func DatasourceAccess(database string, table string, datasetID int) PermissionView {
+	return PermissionView{Permission: "datasource_access", ViewMenu: fmt.Sprintf("[%s].[%s](id:%d)", database, table, datasetID)}
+}
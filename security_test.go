@@ -0,0 +1,106 @@
+package preset
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRoles_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate successful response with mock data
+		assert.Equal(t, "/api/v1/security/roles/", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"count": 2, "ids": [1, 2], "result": [{"id": 1, "name": "Admin"}, {"id": 2, "name": "finance_analysts"}]}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	roles, err := superset.ListRoles(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, *roles, 2)
+	assert.Equal(t, "finance_analysts", (*roles)[1].Name)
+}
+
+func TestCreateRole_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name": "finance_analysts"}`, string(body))
+
+		// Simulate a 201 created response
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 7, "result": {"name": "finance_analysts"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	id, err := superset.CreateRole(context.Background(), "finance_analysts")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+}
+
+func TestSetRolePermissions(t *testing.T) {
+	var granted string
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case "/api/v1/security/permissions-resources/":
+			w.Write([]byte(`{
+				"count": 3,
+				"result": [
+					{"id": 31, "permission": {"id": 1, "name": "datasource_access"}, "view_menu": {"id": 11, "name": "[warehouse].[orders](id:12)"}},
+					{"id": 32, "permission": {"id": 2, "name": "schema_access"}, "view_menu": {"id": 12, "name": "[warehouse].[finance]"}},
+					{"id": 33, "permission": {"id": 3, "name": "database_access"}, "view_menu": {"id": 13, "name": "[warehouse].(id:1)"}}
+				]
+			}`))
+		case "/api/v1/security/roles/7/permissions":
+			body, _ := io.ReadAll(r.Body)
+			granted = string(body)
+			w.Write([]byte(`{"result": {"permission_view_menu_ids": [32, 31]}}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	err := superset.SetRolePermissions(context.Background(), 7, []PermissionView{
+		SchemaAccess("warehouse", "finance"),
+		DatasourceAccess("warehouse", "orders", 12),
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"permission_view_menu_ids": [32, 31]}`, granted)
+
+	err = superset.SetRolePermissions(context.Background(), 7, []PermissionView{SchemaAccess("warehouse", "hr")})
+	assert.EqualError(t, err, "unknown permissions: schema_access on [warehouse].[hr]")
+}
+
+func TestPermissionHelpers(t *testing.T) {
+	assert.Equal(t, PermissionView{Permission: "database_access", ViewMenu: "[warehouse].(id:1)"}, DatabaseAccess("warehouse", 1))
+	assert.Equal(t, PermissionView{Permission: "all_datasource_access", ViewMenu: "all_datasource_access"}, AllDatasourceAccess())
+}
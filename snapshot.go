@@ -0,0 +1,260 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Version of the AccessSnapshot document written by this SDK
+const SnapshotVersion = 1
+
+// AccessSnapshot is the access matrix of every team at a point in time, stored as JSON
+type AccessSnapshot struct {
+	Version int            `json:"version"`
+	TakenAt time.Time      `json:"taken_at"`
+	Teams   []TeamSnapshot `json:"teams"`
+}
+
+// TeamSnapshot is a team's access in an AccessSnapshot
+type TeamSnapshot struct {
+	ID     int          `json:"id"`
+	Name   string       `json:"name"`
+	Title  string       `json:"title"`
+	Access AccessMatrix `json:"access"`
+}
+
+// AccessChangeKind is the kind of an AccessChange
+type AccessChangeKind string
+
+const (
+	ACCESS_TEAM_ADDED        AccessChangeKind = "team_added"
+	ACCESS_TEAM_REMOVED      AccessChangeKind = "team_removed"
+	ACCESS_WORKSPACE_ADDED   AccessChangeKind = "workspace_added"
+	ACCESS_WORKSPACE_REMOVED AccessChangeKind = "workspace_removed"
+	ACCESS_USER_ADDED        AccessChangeKind = "user_added"
+	ACCESS_USER_REMOVED      AccessChangeKind = "user_removed"
+	ACCESS_ROLE_ESCALATED    AccessChangeKind = "role_escalated"
+	ACCESS_ROLE_DEMOTED      AccessChangeKind = "role_demoted"
+	ACCESS_ROLE_CHANGED      AccessChangeKind = "role_changed"
+)
+
+// AccessChange is a difference between two snapshots
+type AccessChange struct {
+	Kind   AccessChangeKind `json:"kind"`
+	TeamID int              `json:"team_id"`
+	// Workspace the change is in, 0 for team-level changes
+	WorkspaceID int `json:"workspace_id,omitempty"`
+	// User the change is about, 0 for team and workspace changes
+	UserID int    `json:"user_id,omitempty"`
+	Email  string `json:"email,omitempty"`
+	// Role before and after, empty where there was none; team and workspace titles for
+	// team and workspace changes
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// AccessDiff lists the changes between two snapshots
+type AccessDiff struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Changes []AccessChange `json:"changes"`
+}
+
+// Team roles from least to most privileged, by name
+var teamRoleRanks = map[string]int{
+	"User":  1,
+	"Admin": 2,
+}
+
+// Workspace roles from least to most privileged, by identifier
+var workspaceRoleRanks = map[string]int{
+	"PresetNoAccess":       0,
+	"PresetDashboardsOnly": 1,
+	"PresetReportsOnly":    2,
+	"PresetGamma":          3,
+	"PresetBeta":           4,
+	"PresetAlpha":          5,
+	"Admin":                6,
+}
+
+// Snapshots the access matrix of every team the client can see
+func (c *PresetClient) TakeAccessSnapshot(ctx context.Context, authToken *string) (*AccessSnapshot, error) {
+	teams, err := c.GetAllTeams(authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &AccessSnapshot{Version: SnapshotVersion, TakenAt: time.Now().UTC(), Teams: []TeamSnapshot{}}
+	for _, team := range *teams {
+		matrix, err := c.BuildAccessMatrix(ctx, team.ID, authToken)
+		if err != nil {
+			return nil, fmt.Errorf("team %d: %w", team.ID, err)
+		}
+		snapshot.Teams = append(snapshot.Teams, TeamSnapshot{ID: team.ID, Name: team.Name, Title: team.Title, Access: *matrix})
+	}
+	sort.Slice(snapshot.Teams, func(i, j int) bool { return snapshot.Teams[i].ID < snapshot.Teams[j].ID })
+
+	return snapshot, nil
+}
+
+// Writes the snapshot as indented JSON
+func (s *AccessSnapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// Reads a snapshot written by Write, rejecting versions this SDK doesn't know
+func ReadAccessSnapshot(r io.Reader) (*AccessSnapshot, error) {
+	snapshot := &AccessSnapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version < 1 || snapshot.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	return snapshot, nil
+}
+
+// Returns the changes from previous to current. A user added to a team also gets an escalation for each
+// workspace role they arrive with; a removed user only gets ACCESS_USER_REMOVED.
+func DiffAccessSnapshots(previous *AccessSnapshot, current *AccessSnapshot) *AccessDiff {
+	diff := &AccessDiff{From: previous.TakenAt, To: current.TakenAt, Changes: []AccessChange{}}
+
+	oldTeams := map[int]TeamSnapshot{}
+	for _, team := range previous.Teams {
+		oldTeams[team.ID] = team
+	}
+	newTeams := map[int]TeamSnapshot{}
+	for _, team := range current.Teams {
+		newTeams[team.ID] = team
+	}
+
+	for _, team := range previous.Teams {
+		if _, ok := newTeams[team.ID]; !ok {
+			diff.Changes = append(diff.Changes, AccessChange{Kind: ACCESS_TEAM_REMOVED, TeamID: team.ID, From: team.Title})
+		}
+	}
+	for _, team := range current.Teams {
+		oldTeam, ok := oldTeams[team.ID]
+		if !ok {
+			diff.Changes = append(diff.Changes, AccessChange{Kind: ACCESS_TEAM_ADDED, TeamID: team.ID, To: team.Title})
+			oldTeam = TeamSnapshot{ID: team.ID}
+		}
+		diff.Changes = append(diff.Changes, diffTeams(oldTeam.Access, team.Access, team.ID)...)
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.TeamID != b.TeamID {
+			return a.TeamID < b.TeamID
+		}
+		if a.WorkspaceID != b.WorkspaceID {
+			return a.WorkspaceID < b.WorkspaceID
+		}
+		return a.Email < b.Email
+	})
+
+	return diff
+}
+
+func diffTeams(previous AccessMatrix, current AccessMatrix, teamID int) []AccessChange {
+	changes := []AccessChange{}
+
+	oldWorkspaces := map[int]AccessWorkspace{}
+	for _, workspace := range previous.Workspaces {
+		oldWorkspaces[workspace.ID] = workspace
+	}
+	newWorkspaces := map[int]AccessWorkspace{}
+	for _, workspace := range current.Workspaces {
+		newWorkspaces[workspace.ID] = workspace
+		if _, ok := oldWorkspaces[workspace.ID]; !ok {
+			changes = append(changes, AccessChange{Kind: ACCESS_WORKSPACE_ADDED, TeamID: teamID, WorkspaceID: workspace.ID, To: workspace.Title})
+		}
+	}
+	for _, workspace := range previous.Workspaces {
+		if _, ok := newWorkspaces[workspace.ID]; !ok {
+			changes = append(changes, AccessChange{Kind: ACCESS_WORKSPACE_REMOVED, TeamID: teamID, WorkspaceID: workspace.ID, From: workspace.Title})
+		}
+	}
+
+	oldUsers := map[int]AccessRow{}
+	for _, user := range previous.Users {
+		oldUsers[user.UserID] = user
+	}
+	newUsers := map[int]bool{}
+
+	for _, user := range current.Users {
+		newUsers[user.UserID] = true
+		oldUser, existed := oldUsers[user.UserID]
+		if !existed {
+			changes = append(changes, AccessChange{Kind: ACCESS_USER_ADDED, TeamID: teamID, UserID: user.UserID, Email: user.Email, To: user.TeamRole})
+			oldUser = AccessRow{Workspaces: map[int]AccessCell{}}
+		} else if oldUser.TeamRole != user.TeamRole {
+			changes = append(changes, AccessChange{
+				Kind:   roleChangeKind(teamRoleRanks, oldUser.TeamRole, user.TeamRole),
+				TeamID: teamID, UserID: user.UserID, Email: user.Email,
+				From: oldUser.TeamRole, To: user.TeamRole,
+			})
+		}
+
+		for _, workspace := range current.Workspaces {
+			before, after := oldUser.Workspaces[workspace.ID], user.Workspaces[workspace.ID]
+			if before.RoleIdentifier == after.RoleIdentifier {
+				continue
+			}
+			changes = append(changes, AccessChange{
+				Kind:   roleChangeKind(workspaceRoleRanks, before.RoleIdentifier, after.RoleIdentifier),
+				TeamID: teamID, WorkspaceID: workspace.ID, UserID: user.UserID, Email: user.Email,
+				From: before.Role, To: after.Role,
+			})
+		}
+	}
+
+	for _, user := range previous.Users {
+		if !newUsers[user.UserID] {
+			changes = append(changes, AccessChange{Kind: ACCESS_USER_REMOVED, TeamID: teamID, UserID: user.UserID, Email: user.Email, From: user.TeamRole})
+		}
+	}
+
+	return changes
+}
+
+// Classifies a role change by rank. Having no role ranks below every role; changes involving a
+// role without a known rank are ACCESS_ROLE_CHANGED.
+func roleChangeKind(ranks map[string]int, from string, to string) AccessChangeKind {
+	rank := func(role string) (int, bool) {
+		if role == "" {
+			return -1, true
+		}
+		r, ok := ranks[role]
+		return r, ok
+	}
+
+	fromRank, fromKnown := rank(from)
+	toRank, toKnown := rank(to)
+	switch {
+	case !fromKnown || !toKnown:
+		return ACCESS_ROLE_CHANGED
+	case toRank > fromRank:
+		return ACCESS_ROLE_ESCALATED
+	case toRank < fromRank:
+		return ACCESS_ROLE_DEMOTED
+	}
+	return ACCESS_ROLE_CHANGED
+}
+
+// Returns the changes that grant more access: escalations, and users added with a role
+func (d *AccessDiff) Escalations() []AccessChange {
+	escalations := []AccessChange{}
+	for _, change := range d.Changes {
+		if change.Kind == ACCESS_ROLE_ESCALATED || (change.Kind == ACCESS_USER_ADDED && change.To != "") {
+			escalations = append(escalations, change)
+		}
+	}
+	return escalations
+}
@@ -0,0 +1,134 @@
+package preset
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a snapshot of one team holding the given users across workspaces 5 and 6
+func testSnapshot(users ...AccessRow) *AccessSnapshot {
+	return &AccessSnapshot{
+		Version: SnapshotVersion,
+		TakenAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Teams: []TeamSnapshot{{
+			ID:    1,
+			Title: "Acme",
+			Access: AccessMatrix{
+				TeamID:     1,
+				Workspaces: []AccessWorkspace{{ID: 5, Title: "Finance"}, {ID: 6, Title: "Sales"}},
+				Users:      users,
+			},
+		}},
+	}
+}
+
+func TestDiffAccessSnapshots(t *testing.T) {
+	viewer := AccessCell{Role: "Viewer", RoleIdentifier: "PresetReportsOnly"}
+	admin := AccessCell{Role: "Workspace Admin", RoleIdentifier: "Admin"}
+
+	previous := testSnapshot(
+		AccessRow{UserID: 10, Email: "ada@example.com", TeamRole: "User", Workspaces: map[int]AccessCell{5: viewer}},
+		AccessRow{UserID: 11, Email: "bob@example.com", TeamRole: "Admin", Workspaces: map[int]AccessCell{5: admin, 6: admin}},
+		AccessRow{UserID: 12, Email: "carl@example.com", TeamRole: "User", Workspaces: map[int]AccessCell{}},
+	)
+	current := testSnapshot(
+		AccessRow{UserID: 10, Email: "ada@example.com", TeamRole: "Admin", Workspaces: map[int]AccessCell{5: admin}},
+		AccessRow{UserID: 11, Email: "bob@example.com", TeamRole: "Admin", Workspaces: map[int]AccessCell{5: viewer}},
+		AccessRow{UserID: 13, Email: "dan@example.com", TeamRole: "User", Workspaces: map[int]AccessCell{6: viewer, 7: admin}},
+	)
+	current.TakenAt = current.TakenAt.Add(24 * time.Hour)
+	current.Teams[0].Access.Workspaces = append(current.Teams[0].Access.Workspaces, AccessWorkspace{ID: 7, Title: "Ops"})
+
+	diff := DiffAccessSnapshots(previous, current)
+	assert.Equal(t, previous.TakenAt, diff.From)
+	assert.Equal(t, []AccessChange{
+		{Kind: ACCESS_ROLE_ESCALATED, TeamID: 1, UserID: 10, Email: "ada@example.com", From: "User", To: "Admin"},
+		{Kind: ACCESS_USER_REMOVED, TeamID: 1, UserID: 12, Email: "carl@example.com", From: "User"},
+		{Kind: ACCESS_USER_ADDED, TeamID: 1, UserID: 13, Email: "dan@example.com", To: "User"},
+		{Kind: ACCESS_ROLE_ESCALATED, TeamID: 1, WorkspaceID: 5, UserID: 10, Email: "ada@example.com", From: "Viewer", To: "Workspace Admin"},
+		{Kind: ACCESS_ROLE_DEMOTED, TeamID: 1, WorkspaceID: 5, UserID: 11, Email: "bob@example.com", From: "Workspace Admin", To: "Viewer"},
+		{Kind: ACCESS_ROLE_DEMOTED, TeamID: 1, WorkspaceID: 6, UserID: 11, Email: "bob@example.com", From: "Workspace Admin"},
+		{Kind: ACCESS_ROLE_ESCALATED, TeamID: 1, WorkspaceID: 6, UserID: 13, Email: "dan@example.com", To: "Viewer"},
+		{Kind: ACCESS_WORKSPACE_ADDED, TeamID: 1, WorkspaceID: 7, To: "Ops"},
+		{Kind: ACCESS_ROLE_ESCALATED, TeamID: 1, WorkspaceID: 7, UserID: 13, Email: "dan@example.com", To: "Workspace Admin"},
+	}, diff.Changes)
+
+	escalations := diff.Escalations()
+	assert.Len(t, escalations, 5)
+}
+
+func TestDiffAccessSnapshots_Teams(t *testing.T) {
+	previous := testSnapshot()
+	current := &AccessSnapshot{Version: SnapshotVersion, Teams: []TeamSnapshot{{ID: 2, Title: "Beta", Access: AccessMatrix{
+		Workspaces: []AccessWorkspace{{ID: 9, Title: "Main"}},
+		Users:      []AccessRow{{UserID: 20, Email: "eve@example.com", TeamRole: "Admin", Workspaces: map[int]AccessCell{}}},
+	}}}}
+
+	diff := DiffAccessSnapshots(previous, current)
+	kinds := []AccessChangeKind{}
+	for _, change := range diff.Changes {
+		kinds = append(kinds, change.Kind)
+	}
+	assert.Equal(t, []AccessChangeKind{ACCESS_TEAM_REMOVED, ACCESS_TEAM_ADDED, ACCESS_USER_ADDED, ACCESS_WORKSPACE_ADDED}, kinds)
+
+	assert.Empty(t, DiffAccessSnapshots(current, current).Changes)
+}
+
+func TestRoleChangeKind_UnknownRole(t *testing.T) {
+	assert.Equal(t, ACCESS_ROLE_CHANGED, roleChangeKind(workspaceRoleRanks, "Admin", "CustomRole"))
+	assert.Equal(t, ACCESS_ROLE_DEMOTED, roleChangeKind(workspaceRoleRanks, "PresetAlpha", ""))
+}
+
+func TestAccessSnapshot_RoundTrip(t *testing.T) {
+	snapshot := testSnapshot(AccessRow{UserID: 10, Email: "ada@example.com", TeamRole: "User", Workspaces: map[int]AccessCell{5: {Role: "Viewer", RoleIdentifier: "PresetReportsOnly"}}})
+
+	var buf bytes.Buffer
+	assert.NoError(t, snapshot.Write(&buf))
+	assert.Contains(t, buf.String(), `"version": 1`)
+
+	read, err := ReadAccessSnapshot(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, read)
+
+	_, err = ReadAccessSnapshot(strings.NewReader(`{"version": 2, "teams": []}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported snapshot version 2")
+}
+
+func TestTakeAccessSnapshot(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/teams":
+			w.Write([]byte(`{"payload": [{"id": 2, "name": "beta", "title": "Beta"}, {"id": 1, "name": "acme", "title": "Acme"}]}`))
+		case "/v1/team/1/memberships", "/v1/team/2/memberships":
+			w.Write([]byte(`{"payload": [{"team_role": {"id": 1, "name": "Admin"}, "user": {"id": 10, "email": "ada@example.com"}}]}`))
+		case "/v1/teams/1/workspaces", "/v1/teams/2/workspaces":
+			w.Write([]byte(`{"payload": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	snapshot, err := client.TakeAccessSnapshot(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, SnapshotVersion, snapshot.Version)
+	assert.False(t, snapshot.TakenAt.IsZero())
+	assert.Len(t, snapshot.Teams, 2)
+	assert.Equal(t, "Acme", snapshot.Teams[0].Title)
+	assert.Equal(t, "Admin", snapshot.Teams[0].Access.Users[0].TeamRole)
+}
@@ -0,0 +1,368 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// QueryState is the lifecycle state of a SQL Lab query
+type QueryState string
+
+const (
+	QUERY_PENDING   QueryState = "pending"
+	QUERY_SCHEDULED QueryState = "scheduled"
+	QUERY_RUNNING   QueryState = "running"
+	QUERY_FETCHING  QueryState = "fetching"
+	QUERY_SUCCESS   QueryState = "success"
+	QUERY_FAILED    QueryState = "failed"
+	QUERY_STOPPED   QueryState = "stopped"
+	QUERY_TIMED_OUT QueryState = "timed_out"
+)
+
+// Reports whether the query has stopped running, successfully or not
+func (q QueryState) Done() bool {
+	return q == QUERY_SUCCESS || q == QUERY_FAILED || q == QUERY_STOPPED || q == QUERY_TIMED_OUT
+}
+
+// GenericDataType is Superset's engine-independent classification of a result column
+type GenericDataType int
+
+const (
+	GENERIC_NUMERIC  GenericDataType = 0
+	GENERIC_STRING   GenericDataType = 1
+	GENERIC_TEMPORAL GenericDataType = 2
+	GENERIC_BOOLEAN  GenericDataType = 3
+)
+
+// How often ExecuteSQL checks on an async query when SQLOptions.PollInterval is not set
+const defaultPollInterval = time.Second
+
+// How long stopping a query whose caller gave up may take
+var stopQueryTimeout = 10 * time.Second
+
+// Runs sql against a database of the workspace and returns an iterator over the result rows.
+//
+// In async mode the query is submitted to the workspace's workers and polled until it finishes;
+// cancelling ctx while it runs stops the query on the server. The caller must close the iterator.
+func (s *SupersetClient) ExecuteSQL(ctx context.Context, databaseID int, schema string, sql string, opts SQLOptions) (*RowIterator, error) {
+	payload := map[string]interface{}{
+		"database_id": databaseID,
+		"schema":      schema,
+		"sql":         sql,
+		"runAsync":    opts.Async,
+	}
+	if opts.Limit > 0 {
+		payload["queryLimit"] = opts.Limit
+	}
+	if opts.TemplateParams != nil {
+		templateParams, err := s.Preset.codec().Marshal(opts.TemplateParams)
+		if err != nil {
+			return nil, err
+		}
+		payload["templateParams"] = string(templateParams)
+	}
+
+	req, err := s.newRequest(ctx, "ExecuteSQL", "POST", "/api/v1/sqllab/execute/", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.Async {
+		body, err := s.doStream(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return newRowIterator(body, s.Preset.codec())
+	}
+
+	aer := AsyncExecuteResponse{}
+	err = s.doDecode(req, &aer)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := s.waitForQuery(ctx, aer.Query, opts.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Status != QUERY_SUCCESS {
+		return nil, fmt.Errorf("query %d %s: %s", query.ID, query.Status, query.ErrorMessage)
+	}
+
+	return s.FetchQueryResults(ctx, query.ResultsKey, opts.Limit)
+}
+
+// Returns the current status of a SQL Lab query
+func (s *SupersetClient) GetQuery(ctx context.Context, queryID int) (*Query, error) {
+	req, err := s.newRequest(ctx, "GetQuery", "GET", fmt.Sprintf("/api/v1/query/%d", queryID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	qr := QueryResponse{}
+	err = s.doDecode(req, &qr)
+	if err != nil {
+		return nil, err
+	}
+
+	query := qr.Result
+	return &query, nil
+}
+
+// Returns an iterator over the stored results of a finished async query. A limit of 0 fetches all rows.
+func (s *SupersetClient) FetchQueryResults(ctx context.Context, resultsKey string, limit int) (*RowIterator, error) {
+	q := fmt.Sprintf("(key:'%s')", resultsKey)
+	if limit > 0 {
+		q = fmt.Sprintf("(key:'%s',rows:%d)", resultsKey, limit)
+	}
+
+	query := url.Values{}
+	query.Set("q", q)
+
+	req, err := s.newRequest(ctx, "FetchQueryResults", "GET", fmt.Sprintf("/api/v1/sqllab/results/?%s", query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.doStream(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRowIterator(body, s.Preset.codec())
+}
+
+// Stops a running query, identified by the client ID SQL Lab assigned when it was submitted
+func (s *SupersetClient) StopQuery(ctx context.Context, clientID string) error {
+	payload := map[string]interface{}{
+		"client_id": clientID,
+	}
+
+	req, err := s.newRequest(ctx, "StopQuery", "POST", "/api/v1/query/stop", payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequest(req)
+	return err
+}
+
+// Polls a submitted query until it is done, stopping it if ctx is cancelled first
+func (s *SupersetClient) waitForQuery(ctx context.Context, submitted AsyncQuery, interval time.Duration) (*Query, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+
+		if ctx.Err() != nil {
+			// ctx is already cancelled, so the stop request needs a context of its own
+			stopCtx, cancel := context.WithTimeout(context.Background(), stopQueryTimeout)
+			defer cancel()
+
+			err := s.StopQuery(stopCtx, submitted.ClientID)
+			if err != nil {
+				return nil, errors.Join(ctx.Err(), fmt.Errorf("stopping query %d: %w", submitted.QueryID, err))
+			}
+			return nil, ctx.Err()
+		}
+
+		query, err := s.GetQuery(ctx, submitted.QueryID)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return nil, err
+		}
+
+		if query.Status.Done() {
+			return query, nil
+		}
+	}
+}
+
+// RowIterator streams the rows of a SQL Lab result without buffering the whole response.
+//
+// Column metadata is decoded as it is encountered in the response. Superset sends it after
+// the rows, so Columns is only guaranteed to be complete once Next has returned false.
+//
+// Rows are only streamed when the client's Codec returns a TokenDecoder; with other codecs the
+// whole result is decoded up front.
+type RowIterator struct {
+	body    io.ReadCloser
+	decoder TokenDecoder
+	inData  bool
+	done    bool
+	row     Row
+	err     error
+	header  QueryResultHeader
+	// Rows decoded up front when the decoder can't stream them
+	buffered []Row
+}
+
+func newRowIterator(body io.ReadCloser, codec Codec) (*RowIterator, error) {
+	decoder := codec.NewDecoder(body)
+	if numbers, ok := decoder.(interface{ UseNumber() }); ok {
+		numbers.UseNumber()
+	}
+
+	tokens, ok := decoder.(TokenDecoder)
+	if !ok {
+		return bufferRows(body, decoder)
+	}
+
+	token, err := tokens.Token()
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		body.Close()
+		return nil, fmt.Errorf("unexpected query result %v", token)
+	}
+
+	return &RowIterator{body: body, decoder: tokens}, nil
+}
+
+// Decodes a whole result with a decoder that can't stream it
+func bufferRows(body io.ReadCloser, decoder Decoder) (*RowIterator, error) {
+	result := struct {
+		QueryResultHeader
+		Data []Row `json:"data"`
+	}{}
+	err := decoder.Decode(&result)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+
+	return &RowIterator{body: body, done: true, header: result.QueryResultHeader, buffered: result.Data}, nil
+}
+
+// Advances to the next row, returning false when the rows are exhausted or an error occurred
+func (it *RowIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.decoder == nil {
+		if len(it.buffered) == 0 {
+			it.row = nil
+			if it.header.Status == QUERY_FAILED {
+				it.err = fmt.Errorf("query %d failed: %s", it.header.QueryID, it.header.Error)
+			}
+			return false
+		}
+		it.row, it.buffered = it.buffered[0], it.buffered[1:]
+		return true
+	}
+
+	if !it.inData {
+		it.scan()
+	}
+
+	if it.inData {
+		if it.decoder.More() {
+			it.row = Row{}
+			it.err = it.decoder.Decode(&it.row)
+			return it.err == nil
+		}
+
+		// Consume the closing bracket of the data array and whatever follows it
+		_, it.err = it.decoder.Token()
+		it.inData = false
+		it.scan()
+	}
+
+	it.row = nil
+	return false
+}
+
+// Returns the row the iterator is positioned on, keyed by column name
+func (it *RowIterator) Row() Row {
+	return it.row
+}
+
+// Returns the column metadata decoded so far
+func (it *RowIterator) Columns() []ResultColumn {
+	return it.header.Columns
+}
+
+// Returns the ID of the query that produced the results
+func (it *RowIterator) QueryID() int {
+	return it.header.QueryID
+}
+
+// Returns the error that stopped iteration, if any
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// Releases the underlying response body
+func (it *RowIterator) Close() error {
+	return it.body.Close()
+}
+
+// Decodes top-level fields until the data array starts or the response ends
+func (it *RowIterator) scan() {
+	for it.err == nil && !it.done {
+		if !it.decoder.More() {
+			_, it.err = it.decoder.Token()
+			it.done = true
+			if it.err == nil && it.header.Status == QUERY_FAILED {
+				it.err = fmt.Errorf("query %d failed: %s", it.header.QueryID, it.header.Error)
+			}
+			return
+		}
+
+		token, err := it.decoder.Token()
+		if err != nil {
+			it.err = err
+			return
+		}
+		key, _ := token.(string)
+
+		switch key {
+		case "data":
+			token, err := it.decoder.Token()
+			if err != nil {
+				it.err = err
+				return
+			}
+			if delim, ok := token.(json.Delim); ok && delim == '[' {
+				it.inData = true
+				return
+			}
+			// No rows, e.g. for statements that don't return any
+			if token != nil {
+				it.err = fmt.Errorf("unexpected query result data %v", token)
+				return
+			}
+		case "status":
+			it.err = it.decoder.Decode(&it.header.Status)
+		case "query_id":
+			it.err = it.decoder.Decode(&it.header.QueryID)
+		case "columns":
+			it.err = it.decoder.Decode(&it.header.Columns)
+		case "error":
+			it.err = it.decoder.Decode(&it.header.Error)
+		default:
+			skip := json.RawMessage{}
+			it.err = it.decoder.Decode(&skip)
+		}
+	}
+}
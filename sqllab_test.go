@@ -0,0 +1,256 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteSQL_Synchronous(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/sqllab/execute/", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		json.Unmarshal(body, &payload)
+		assert.Equal(t, float64(1), payload["database_id"])
+		assert.Equal(t, "public", payload["schema"])
+		assert.Equal(t, false, payload["runAsync"])
+
+		// Simulate successful response; Superset sends the rows before the columns
+		response := []byte(`{
+			"status": "success",
+			"query_id": 42,
+			"data": [
+				{"region": "EMEA", "revenue": 12345678901234},
+				{"region": "APAC", "revenue": 1.5}
+			],
+			"columns": [
+				{"column_name": "region", "name": "region", "type": "VARCHAR", "is_dttm": false, "type_generic": 1},
+				{"column_name": "revenue", "name": "revenue", "type": "NUMERIC", "is_dttm": false, "type_generic": 0}
+			],
+			"query": {"id": "a1b2c3"}
+		}`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	rows, err := superset.ExecuteSQL(context.Background(), 1, "public", "SELECT region, revenue FROM sales", SQLOptions{})
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	regions := []string{}
+	for rows.Next() {
+		regions = append(regions, rows.Row()["region"].(string))
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, []string{"EMEA", "APAC"}, regions)
+	assert.Equal(t, 42, rows.QueryID())
+	assert.Len(t, rows.Columns(), 2)
+	assert.Equal(t, GENERIC_NUMERIC, rows.Columns()[1].TypeGeneric)
+}
+
+func TestExecuteSQL_NoRows(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate the response to a statement without results
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "success", "query_id": 43, "data": null, "columns": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	rows, err := superset.ExecuteSQL(context.Background(), 1, "public", "DELETE FROM sales", SQLOptions{})
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, 43, rows.QueryID())
+}
+
+func TestExecuteSQL_Asynchronous(t *testing.T) {
+	polls := 0
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sqllab/execute/":
+			// Simulate the accepted response of an async query
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"query": {"id": "a1b2c3", "queryId": 44, "state": "pending"}}`))
+		case "/api/v1/query/44":
+			polls++
+			status := "running"
+			if polls > 1 {
+				status = "success"
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 44, "result": {"id": 44, "status": "` + status + `", "results_key": "key-44"}}`))
+		case "/api/v1/sqllab/results/":
+			assert.Equal(t, "(key:'key-44',rows:10)", r.URL.Query().Get("q"))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"status": "success", "query_id": 44, "data": [{"n": 1}], "columns": [{"name": "n", "type_generic": 0}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	rows, err := superset.ExecuteSQL(context.Background(), 1, "public", "SELECT 1 AS n", SQLOptions{Async: true, Limit: 10, PollInterval: time.Millisecond})
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	assert.True(t, rows.Next())
+	assert.Equal(t, json.Number("1"), rows.Row()["n"])
+	assert.False(t, rows.Next())
+	assert.Equal(t, 2, polls)
+}
+
+func TestExecuteSQL_CancelStopsQuery(t *testing.T) {
+	stopped := make(chan string, 1)
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sqllab/execute/":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"query": {"id": "a1b2c3", "queryId": 45, "state": "pending"}}`))
+		case "/api/v1/query/45":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 45, "result": {"id": 45, "status": "running"}}`))
+		case "/api/v1/query/stop":
+			body, _ := io.ReadAll(r.Body)
+			stopped <- string(body)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"result": "OK"}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := superset.ExecuteSQL(ctx, 1, "public", "SELECT pg_sleep(60)", SQLOptions{Async: true, PollInterval: time.Millisecond})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, `{"client_id":"a1b2c3"}`, <-stopped)
+}
+
+func TestExecuteSQL_StopIsBoundedAndReported(t *testing.T) {
+	timeout := stopQueryTimeout
+	stopQueryTimeout = 50 * time.Millisecond
+	defer func() { stopQueryTimeout = timeout }()
+
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sqllab/execute/":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"query": {"id": "a1b2c3", "queryId": 46, "state": "pending"}}`))
+		case "/api/v1/query/46":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 46, "result": {"id": 46, "status": "running"}}`))
+		case "/api/v1/query/stop":
+			// Simulate a workspace that never answers
+			io.ReadAll(r.Body)
+			<-r.Context().Done()
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := superset.ExecuteSQL(ctx, 1, "public", "SELECT pg_sleep(60)", SQLOptions{Async: true, PollInterval: time.Millisecond})
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "stopping query 46")
+}
+
+func TestExecuteSQL_UnexpectedData(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "success", "data": {"n": 1}, "query_id": 47, "columns": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	rows, err := superset.ExecuteSQL(context.Background(), 1, "public", "SELECT 1", SQLOptions{})
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	assert.False(t, rows.Next())
+	assert.EqualError(t, rows.Err(), "unexpected query result data {")
+}
+
+func TestExecuteSQL_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+	superset := &SupersetClient{BaseURL: mockServer.URL, Preset: client}
+
+	_, err := superset.ExecuteSQL(context.Background(), 1, "public", "SELECT 1", SQLOptions{})
+	assert.Error(t, err)
+}
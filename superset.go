@@ -0,0 +1,97 @@
+package preset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Number of items requested per page when listing Superset resources
+const supersetPageSize int = 100
+
+// SupersetClient talks to the Superset API of a single Preset workspace
+type SupersetClient struct {
+	BaseURL   string
+	Preset    *PresetClient
+	AuthToken *string
+	// IDs of the workspace and its team, reported to the client's Instrumentation
+	TeamID      int
+	WorkspaceID int
+}
+
+// Returns a client bound to the Superset API served at the workspace's hostname
+func (c *PresetClient) NewSupersetClient(workspace Workspace, authToken *string) *SupersetClient {
+	return &SupersetClient{
+		BaseURL:     fmt.Sprintf("https://%s", workspace.Hostname),
+		Preset:      c,
+		AuthToken:   authToken,
+		TeamID:      workspace.TeamID,
+		WorkspaceID: workspace.ID,
+	}
+}
+
+// Builds a request against the workspace API for the named SDK operation, encoding payload as the
+// JSON body when given
+func (s *SupersetClient) newRequest(ctx context.Context, operation string, method string, path string, payload interface{}) (*http.Request, error) {
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := s.Preset.codec().Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payloadBytes)
+	}
+
+	ctx = withOperation(ctx, operation, s.TeamID, s.WorkspaceID)
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", s.BaseURL, path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func (s *SupersetClient) doRequest(req *http.Request) ([]byte, error) {
+	return s.Preset.doRequest(req, s.AuthToken)
+}
+
+func (s *SupersetClient) doStream(req *http.Request) (io.ReadCloser, error) {
+	return s.Preset.doStream(req, s.AuthToken)
+}
+
+func (s *SupersetClient) doDecode(req *http.Request, v interface{}) error {
+	return s.Preset.doDecode(req, s.AuthToken, v)
+}
+
+// Fetches every page of a Superset list endpoint and returns the concatenated results
+func getAllPages[T any](ctx context.Context, s *SupersetClient, operation string, path string) ([]T, error) {
+	items := []T{}
+
+	for page := 0; ; page++ {
+		query := url.Values{}
+		query.Set("q", fmt.Sprintf("(page:%d,page_size:%d)", page, supersetPageSize))
+
+		req, err := s.newRequest(ctx, operation, "GET", fmt.Sprintf("%s?%s", path, query.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		lr := SupersetListResponse[T]{}
+		err = s.doDecode(req, &lr)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, lr.Result...)
+		if len(lr.Result) < supersetPageSize || len(items) >= lr.Count {
+			return items, nil
+		}
+	}
+}
@@ -2,7 +2,7 @@ package preset
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,21 +16,76 @@ const (
 	TEAM_USER TeamRoleEnum = 2
 )
 
+// BillingStatus is the state of a team's billing account. Values the SDK doesn't know are kept as sent.
+type BillingStatus string
+
+const (
+	BILLING_CURRENT  BillingStatus = "CURRENT"
+	BILLING_PAST_DUE BillingStatus = "PAST_DUE"
+	BILLING_UNKNOWN  BillingStatus = "UNKNOWN"
+)
+
+// SubscriptionStatus is the state of a team's Preset subscription
+type SubscriptionStatus string
+
+const (
+	SUBSCRIPTION_TRIAL    SubscriptionStatus = "TRIAL"
+	SUBSCRIPTION_PAID     SubscriptionStatus = "PAID"
+	SUBSCRIPTION_EXPIRED  SubscriptionStatus = "EXPIRED"
+	SUBSCRIPTION_CANCELED SubscriptionStatus = "CANCELED"
+	SUBSCRIPTION_UNKNOWN  SubscriptionStatus = "UNKNOWN"
+)
+
+// Tier is the Preset plan a team is on
+type Tier string
+
+const (
+	TIER_STARTER      Tier = "STARTER"
+	TIER_PROFESSIONAL Tier = "PROFESSIONAL"
+	TIER_ENTERPRISE   Tier = "ENTERPRISE"
+	TIER_UNKNOWN      Tier = "UNKNOWN"
+)
+
+// Returns the status, or BILLING_UNKNOWN if the SDK doesn't know it
+func (s BillingStatus) OrUnknown() BillingStatus {
+	switch s {
+	case BILLING_CURRENT, BILLING_PAST_DUE:
+		return s
+	}
+	return BILLING_UNKNOWN
+}
+
+// Returns the status, or SUBSCRIPTION_UNKNOWN if the SDK doesn't know it
+func (s SubscriptionStatus) OrUnknown() SubscriptionStatus {
+	switch s {
+	case SUBSCRIPTION_TRIAL, SUBSCRIPTION_PAID, SUBSCRIPTION_EXPIRED, SUBSCRIPTION_CANCELED:
+		return s
+	}
+	return SUBSCRIPTION_UNKNOWN
+}
+
+// Returns the tier, or TIER_UNKNOWN if the SDK doesn't know it
+func (t Tier) OrUnknown() Tier {
+	switch t {
+	case TIER_STARTER, TIER_PROFESSIONAL, TIER_ENTERPRISE:
+		return t
+	}
+	return TIER_UNKNOWN
+}
+
 // Returns all Preset teams that the user admin token has access to
 func (c *PresetClient) GetAllTeams(authToken *string) (*[]Team, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/teams", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
+	return c.getAllTeams(context.Background(), authToken)
+}
 
-	body, err := c.doRequest(req, authToken)
+func (c *PresetClient) getAllTeams(ctx context.Context, authToken *string) (*[]Team, error) {
+	req, err := http.NewRequestWithContext(withOperation(ctx, "GetAllTeams", 0, 0), "GET", fmt.Sprintf("%s/v1/teams", c.BaseURL), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	tr := TeamResponse{}
-	err = json.Unmarshal(body, &tr)
-
+	err = c.doDecode(req, authToken, &tr)
 	if err != nil {
 		return nil, err
 	}
@@ -41,18 +96,17 @@ func (c *PresetClient) GetAllTeams(authToken *string) (*[]Team, error) {
 
 // Returns all the members belonging to a given team
 func (c *PresetClient) GetTeamMembership(teamID int, workspaceID int, authToken *string) (*[]TeamMembership, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/team/%d/memberships", c.BaseURL, teamID), nil)
-	if err != nil {
-		return nil, err
-	}
+	return c.getTeamMembership(context.Background(), teamID, workspaceID, authToken)
+}
 
-	body, err := c.doRequest(req, authToken)
+func (c *PresetClient) getTeamMembership(ctx context.Context, teamID int, workspaceID int, authToken *string) (*[]TeamMembership, error) {
+	req, err := http.NewRequestWithContext(withOperation(ctx, "GetTeamMembership", teamID, workspaceID), "GET", fmt.Sprintf("%s/v1/team/%d/memberships", c.BaseURL, teamID), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	tmr := TeamMembershipResponse{}
-	err = json.Unmarshal(body, &tmr)
+	err = c.doDecode(req, authToken, &tmr)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +117,10 @@ func (c *PresetClient) GetTeamMembership(teamID int, workspaceID int, authToken
 
 // Updates a given user's team role
 func (c *PresetClient) UpdateUserTeamRole(teamID int, userID int, roleID TeamRoleEnum, authToken *string) (*TeamMembership, error) {
+	return c.updateUserTeamRole(context.Background(), teamID, userID, roleID, authToken)
+}
+
+func (c *PresetClient) updateUserTeamRole(ctx context.Context, teamID int, userID int, roleID TeamRoleEnum, authToken *string) (*TeamMembership, error) {
 	// Validate roleID
 	if roleID != TEAM_ADMIN && roleID != TEAM_USER {
 		return nil, fmt.Errorf("invalid role ID")
@@ -74,24 +132,19 @@ func (c *PresetClient) UpdateUserTeamRole(teamID int, userID int, roleID TeamRol
 	}
 
 	// Convert the payload map to JSON
-	payloadBytes, err := json.Marshal(payload)
+	payloadBytes, err := c.codec().Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/v1/team/%d/memberships/%d", c.BaseURL, teamID, userID), bytes.NewReader(payloadBytes))
+	req, err := http.NewRequestWithContext(withOperation(ctx, "UpdateUserTeamRole", teamID, 0), "PUT", fmt.Sprintf("%s/v1/team/%d/memberships/%d", c.BaseURL, teamID, userID), bytes.NewReader(payloadBytes))
 	
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req, authToken)
-	if err != nil {
-		return nil, err
-	}
-
 	tmur := TeamMembershipUpdateResponse{}
-	err = json.Unmarshal(body, &tmur)
+	err = c.doDecode(req, authToken, &tmur)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +155,7 @@ func (c *PresetClient) UpdateUserTeamRole(teamID int, userID int, roleID TeamRol
 
 // Deletes a member from the team
 func (c *PresetClient) DeleteTeamMembership(teamID int, userID int, authToken *string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/v1/team/%d/memberships/%d", c.BaseURL, teamID, userID), nil)
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "DeleteTeamMembership", teamID, 0), "DELETE", fmt.Sprintf("%s/v1/team/%d/memberships/%d", c.BaseURL, teamID, userID), nil)
 	if err != nil {
 		return err
 	}
@@ -118,7 +171,7 @@ func (c *PresetClient) DeleteTeamMembership(teamID int, userID int, authToken *s
 	}
 
 	var apiResponse ApiResponse
-	err = json.Unmarshal(body, &apiResponse)
+	err = c.codec().NewDecoder(bytes.NewReader(body)).Decode(&apiResponse)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package preset
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -287,3 +288,25 @@ func TestDeleteTeamMembership_InternalServerError(t *testing.T) {
 	err := client.DeleteTeamMembership(1, 456, nil)
 	assert.Error(t, err)
 }
+
+func TestTeam_TypedFields(t *testing.T) {
+	data := []byte(`{"id":831,"billing_status":"CURRENT","created_on":"2021-05-17T21:36:16.216107","downgraded_at":null,"subscription_status":"GRANDFATHERED","tier":"ENTERPRISE","trial_expiry":"2021-06-14T15:16:54.299781"}`)
+
+	team := Team{}
+	err := json.Unmarshal(data, &team)
+	assert.NoError(t, err)
+	assert.Equal(t, BILLING_CURRENT, team.BillingStatus.OrUnknown())
+	assert.Equal(t, SUBSCRIPTION_UNKNOWN, team.SubscriptionStatus.OrUnknown())
+	assert.Equal(t, SubscriptionStatus("GRANDFATHERED"), team.SubscriptionStatus)
+	assert.Equal(t, TIER_ENTERPRISE, team.Tier.OrUnknown())
+	assert.Equal(t, 2021, team.CreatedOn.Time.Year())
+	assert.True(t, team.DowngradedAt.IsZero())
+
+	encoded, err := json.Marshal(team)
+	assert.NoError(t, err)
+	roundTrip := map[string]interface{}{}
+	json.Unmarshal(encoded, &roundTrip)
+	assert.Equal(t, "2021-05-17T21:36:16.216107", roundTrip["created_on"])
+	assert.Nil(t, roundTrip["downgraded_at"])
+	assert.Equal(t, "GRANDFATHERED", roundTrip["subscription_status"])
+}
@@ -0,0 +1,95 @@
+package preset
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Layouts the Preset and Superset APIs use for timestamps. Values without a zone are in UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// Timestamp is a time decoded from any of the API's timestamp formats. A JSON null or empty string
+// decodes to the zero time. The original text, empty or not, is kept and written back as long as
+// the time is not changed, so decoding and re-encoding a payload doesn't alter it.
+//
+// A value in a format the SDK doesn't know doesn't fail the decode: it is kept in Raw and Time is
+// left zero.
+//
+// The time is a named field rather than embedded, so that time.Time's own text and gob encodings
+// are not promoted and the JSON encoding is the only one Timestamp has.
+type Timestamp struct {
+	Time time.Time
+	// The text the timestamp was decoded from
+	Raw    string
+	parsed time.Time
+	empty  bool
+}
+
+// Parses value in any of the API's timestamp formats
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		parsed, err := time.ParseInLocation(layout, value, time.UTC)
+		if err == nil {
+			return Timestamp{Time: parsed, Raw: value, parsed: parsed}, nil
+		}
+	}
+
+	return Timestamp{}, fmt.Errorf("unsupported timestamp format %q", value)
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Timestamp{}
+		return nil
+	}
+
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		*t = Timestamp{empty: true}
+		return nil
+	}
+
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		*t = Timestamp{Raw: value}
+		return nil
+	}
+
+	*t = parsed
+	return nil
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.Raw != "" && t.Time.Equal(t.parsed) {
+		return json.Marshal(t.Raw)
+	}
+
+	if t.empty && t.Time.IsZero() {
+		return []byte(`""`), nil
+	}
+
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}
+
+// Reports whether the timestamp is the zero time, e.g. because the API sent null
+func (t Timestamp) IsZero() bool {
+	return t.Time.IsZero()
+}
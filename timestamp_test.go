@@ -0,0 +1,121 @@
+package preset
+
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamp_Formats(t *testing.T) {
+	cases := map[string]time.Time{
+		`"2021-05-17T21:36:16.216107"`: time.Date(2021, 5, 17, 21, 36, 16, 216107000, time.UTC),
+		`"2023-08-01T12:00:00Z"`:       time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC),
+		`"2023-08-01T12:00:00+02:00"`:  time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC),
+		`"2023-08-01T12:00:00+0200"`:   time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC),
+		`"2023-08-01 12:00:00"`:        time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC),
+		`"2023-08-01 12:00:00.5"`:      time.Date(2023, 8, 1, 12, 0, 0, 500000000, time.UTC),
+		`"2023-08-01 12:00:00.5-0700"`: time.Date(2023, 8, 1, 19, 0, 0, 500000000, time.UTC),
+		`"2023-08-01 12:00:00+02:00"`:  time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC),
+		`"2023-08-01"`:                 time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for data, expected := range cases {
+		timestamp := Timestamp{}
+		err := json.Unmarshal([]byte(data), &timestamp)
+		assert.NoError(t, err, data)
+		assert.True(t, expected.Equal(timestamp.Time), data)
+
+		// Unchanged values are written back exactly as they were read
+		encoded, err := json.Marshal(timestamp)
+		assert.NoError(t, err)
+		assert.Equal(t, data, string(encoded))
+	}
+}
+
+func TestTimestamp_Null(t *testing.T) {
+	timestamp := Timestamp{}
+	err := json.Unmarshal([]byte(`null`), &timestamp)
+	assert.NoError(t, err)
+	assert.True(t, timestamp.IsZero())
+
+	encoded, err := json.Marshal(timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(encoded))
+}
+
+func TestTimestamp_EmptyString(t *testing.T) {
+	timestamp := Timestamp{}
+	err := json.Unmarshal([]byte(`""`), &timestamp)
+	assert.NoError(t, err)
+	assert.True(t, timestamp.IsZero())
+
+	encoded, err := json.Marshal(timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, `""`, string(encoded))
+
+	// Setting a time replaces the empty string
+	timestamp.Time = time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	encoded, err = json.Marshal(timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, `"2023-08-01T12:00:00Z"`, string(encoded))
+}
+
+func TestTimestamp_OnlyEncodesAsJSON(t *testing.T) {
+	var timestamp interface{} = Timestamp{}
+	_, isTextMarshaler := timestamp.(encoding.TextMarshaler)
+	_, isGobEncoder := timestamp.(gob.GobEncoder)
+	assert.False(t, isTextMarshaler)
+	assert.False(t, isGobEncoder)
+}
+
+func TestTimestamp_Modified(t *testing.T) {
+	timestamp, err := ParseTimestamp("2021-05-17T21:36:16.216107")
+	assert.NoError(t, err)
+
+	timestamp.Time = timestamp.Time.Add(time.Hour)
+	encoded, err := json.Marshal(timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, `"2021-05-17T22:36:16.216107Z"`, string(encoded))
+}
+
+func TestTimestamp_UnknownFormat(t *testing.T) {
+	timestamp := Timestamp{}
+	err := json.Unmarshal([]byte(`"17/05/2021"`), &timestamp)
+	assert.NoError(t, err)
+	assert.True(t, timestamp.IsZero())
+	assert.Equal(t, "17/05/2021", timestamp.Raw)
+
+	encoded, err := json.Marshal(timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, `"17/05/2021"`, string(encoded))
+
+	_, err = ParseTimestamp("17/05/2021")
+	assert.EqualError(t, err, `unsupported timestamp format "17/05/2021"`)
+}
+
+func TestTimestamp_UnknownFormatDoesntFailList(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"payload": [{"id": 1, "name": "acme", "created_on": "17/05/2021", "trial_expiry": "2023-08-01 12:00:00.5"}]}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	teams, err := client.GetAllTeams(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", (*teams)[0].Name)
+	assert.True(t, (*teams)[0].CreatedOn.IsZero())
+	assert.Equal(t, "17/05/2021", (*teams)[0].CreatedOn.Raw)
+	assert.Equal(t, 2023, (*teams)[0].TrialExpiry.Time.Year())
+}
@@ -0,0 +1,273 @@
+package preset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUsageMetricsDisabled is returned by the usage methods when the team's feature flags don't
+// enable the usage metrics dashboard
+var ErrUsageMetricsDisabled = errors.New("usage metrics dashboard is not enabled for the team")
+
+// Columns of the usage dashboard's dataset that usage metrics are computed from. Change them if
+// the dataset of your team's dashboard names its columns or actions differently.
+var UsageDatasetColumns = UsageColumns{
+	Time:                "dttm",
+	Workspace:           "workspace_id",
+	User:                "user_id",
+	Action:              "action",
+	DashboardViewAction: "dashboard_view",
+	QueryAction:         "query",
+}
+
+// Rows of usage data requested at a time. Superset treats a row limit of 0 as its default limit,
+// so results are paged explicitly rather than risk being truncated.
+var usagePageSize = 10000
+
+// Locates the usage metrics dashboard of a team from its usage_metrics_* feature flags
+func (c *PresetClient) GetUsageDashboard(ctx context.Context, teamID int, authToken *string) (*UsageDashboard, error) {
+	flags, err := c.getTeamFeatureFlags(ctx, teamID, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !flags.UsageMetricsDashEnabled || flags.UsageMetricsLink == "" || flags.UsageMetricsResourceID == "" {
+		return nil, ErrUsageMetricsDisabled
+	}
+
+	link, err := url.Parse(flags.UsageMetricsLink)
+	if err != nil {
+		return nil, fmt.Errorf("usage_metrics_link: %w", err)
+	}
+	if link.Scheme == "" || link.Host == "" {
+		return nil, fmt.Errorf("usage_metrics_link %q is not an absolute URL", flags.UsageMetricsLink)
+	}
+
+	return &UsageDashboard{
+		WorkspaceURL: fmt.Sprintf("%s://%s", link.Scheme, link.Host),
+		ResourceID:   flags.UsageMetricsResourceID,
+		RLSRules:     flags.UsageMetricsRlsRules,
+	}, nil
+}
+
+// Returns the activity of a workspace between since and until, both inclusive dates, as recorded
+// by the team's usage metrics dashboard
+func (c *PresetClient) GetWorkspaceUsage(ctx context.Context, teamID int, workspaceID int, since time.Time, until time.Time, authToken *string) (*WorkspaceUsage, error) {
+	if until.Before(since) {
+		return nil, fmt.Errorf("invalid usage window")
+	}
+
+	usage, err := c.queryUsage(ctx, "GetWorkspaceUsage", teamID, &workspaceID, since, until, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspaceUsage, found := usage[workspaceID]; found {
+		return workspaceUsage, nil
+	}
+	return newWorkspaceUsage(workspaceID, since, until), nil
+}
+
+// Returns the activity of every workspace of a team between since and until. Workspaces without
+// any activity in the window are reported with zero counts.
+func (c *PresetClient) GetTeamUsage(ctx context.Context, teamID int, since time.Time, until time.Time, authToken *string) (*TeamUsage, error) {
+	if until.Before(since) {
+		return nil, fmt.Errorf("invalid usage window")
+	}
+
+	workspaces, err := c.getAllWorkspaces(ctx, teamID, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := c.queryUsage(ctx, "GetTeamUsage", teamID, nil, since, until, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	teamUsage := TeamUsage{TeamID: teamID, Workspaces: []WorkspaceUsage{}}
+	for _, workspace := range *workspaces {
+		workspaceUsage, found := usage[workspace.ID]
+		if !found {
+			workspaceUsage = newWorkspaceUsage(workspace.ID, since, until)
+		}
+		teamUsage.Workspaces = append(teamUsage.Workspaces, *workspaceUsage)
+	}
+
+	return &teamUsage, nil
+}
+
+// Adds up the usage of all workspaces. Users active in several workspaces are counted once when
+// every workspace reports who was active, and once per workspace otherwise.
+func (t *TeamUsage) Total() UsageMetrics {
+	total := UsageMetrics{}
+	users := map[int]bool{}
+	distinct := true
+
+	for _, workspace := range t.Workspaces {
+		total.ActiveUsers += workspace.ActiveUsers
+		total.DashboardViews += workspace.DashboardViews
+		total.QueryCount += workspace.QueryCount
+
+		if workspace.ActiveUserIDs == nil {
+			distinct = false
+		}
+		for _, id := range workspace.ActiveUserIDs {
+			users[id] = true
+		}
+	}
+
+	if distinct && len(t.Workspaces) > 0 {
+		total.ActiveUsers = len(users)
+	}
+
+	return total
+}
+
+// Queries the dataset of the team's usage dashboard through Superset's chart data API and returns
+// the usage of each workspace with activity in the window, optionally for a single workspace.
+// Requests are reported to Instrumentation as part of operation.
+func (c *PresetClient) queryUsage(ctx context.Context, operation string, teamID int, workspaceID *int, since time.Time, until time.Time, authToken *string) (map[int]*WorkspaceUsage, error) {
+	dashboard, err := c.GetUsageDashboard(ctx, teamID, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	superset := &SupersetClient{BaseURL: dashboard.WorkspaceURL, Preset: c, AuthToken: authToken, TeamID: teamID}
+
+	req, err := superset.newRequest(ctx, operation, "GET", fmt.Sprintf("/api/v1/dashboard/%s/datasets", url.PathEscape(dashboard.ResourceID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ddr := DashboardDatasetsResponse{}
+	err = superset.doDecode(req, &ddr)
+	if err != nil {
+		return nil, err
+	}
+	if len(ddr.Result) == 0 {
+		return nil, fmt.Errorf("usage dashboard %s has no dataset", dashboard.ResourceID)
+	}
+
+	columns := UsageDatasetColumns
+	filters := []map[string]interface{}{{
+		"col": columns.Time,
+		"op":  "TEMPORAL_RANGE",
+		// The end of a Superset time range is exclusive
+		"val": fmt.Sprintf("%s : %s", since.Format("2006-01-02"), until.AddDate(0, 0, 1).Format("2006-01-02")),
+	}}
+	if workspaceID != nil {
+		filters = append(filters, map[string]interface{}{"col": columns.Workspace, "op": "==", "val": *workspaceID})
+	}
+
+	usage := map[int]*WorkspaceUsage{}
+	for offset := 0; ; offset += usagePageSize {
+		payload := map[string]interface{}{
+			"datasource": map[string]interface{}{"id": ddr.Result[0].ID, "type": "table"},
+			"queries": []map[string]interface{}{{
+				"columns": []string{columns.Workspace, columns.User},
+				"metrics": []map[string]interface{}{
+					countAction(columns, columns.DashboardViewAction, "dashboard_views"),
+					countAction(columns, columns.QueryAction, "query_count"),
+				},
+				"filters": filters,
+				// Pages are only consistent if the rows come in a stable order
+				"orderby":    [][]interface{}{{columns.Workspace, true}, {columns.User, true}},
+				"row_limit":  usagePageSize,
+				"row_offset": offset,
+			}},
+			"result_format": "json",
+			"result_type":   "full",
+		}
+
+		req, err = superset.newRequest(ctx, operation, "POST", "/api/v1/chart/data", payload)
+		if err != nil {
+			return nil, err
+		}
+
+		cdr := ChartDataResponse{}
+		err = superset.doDecode(req, &cdr)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := 0
+		for _, result := range cdr.Result {
+			rows += len(result.Data)
+			for _, row := range result.Data {
+				workspace, err := usageCount(columns.Workspace, row[columns.Workspace])
+				if err != nil {
+					return nil, err
+				}
+				user, err := usageCount(columns.User, row[columns.User])
+				if err != nil {
+					return nil, err
+				}
+				views, err := usageCount("dashboard_views", row["dashboard_views"])
+				if err != nil {
+					return nil, err
+				}
+				queries, err := usageCount("query_count", row["query_count"])
+				if err != nil {
+					return nil, err
+				}
+
+				workspaceUsage, found := usage[workspace]
+				if !found {
+					workspaceUsage = newWorkspaceUsage(workspace, since, until)
+					usage[workspace] = workspaceUsage
+				}
+				// Rows are grouped by workspace and user, so every row is a distinct active user
+				workspaceUsage.ActiveUsers++
+				workspaceUsage.ActiveUserIDs = append(workspaceUsage.ActiveUserIDs, user)
+				workspaceUsage.DashboardViews += views
+				workspaceUsage.QueryCount += queries
+			}
+		}
+
+		// A short page is the last one
+		if rows < usagePageSize {
+			return usage, nil
+		}
+	}
+}
+
+func newWorkspaceUsage(workspaceID int, since time.Time, until time.Time) *WorkspaceUsage {
+	return &WorkspaceUsage{
+		WorkspaceID:   workspaceID,
+		StartDate:     since.Format("2006-01-02"),
+		EndDate:       until.Format("2006-01-02"),
+		ActiveUserIDs: []int{},
+	}
+}
+
+// Returns an ad hoc metric that counts the rows whose action column holds action
+func countAction(columns UsageColumns, action string, label string) map[string]interface{} {
+	return map[string]interface{}{
+		"expressionType": "SQL",
+		"sqlExpression":  fmt.Sprintf("SUM(CASE WHEN %s = '%s' THEN 1 ELSE 0 END)", columns.Action, strings.ReplaceAll(action, "'", "''")),
+		"label":          label,
+	}
+}
+
+// Converts a value of the chart data response to an int
+func usageCount(column string, value interface{}) (int, error) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(v)
+		if err == nil {
+			return n, nil
+		}
+	case nil:
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("unexpected %s value %v in usage data", column, value)
+}
@@ -0,0 +1,218 @@
+package preset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Serves a team whose usage dashboard lives on the same server, answering chart data queries with
+// the page of rows they ask for
+func mockUsageServer(t *testing.T, enabled bool, rows string, query *map[string]interface{}) *httptest.Server {
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/teams":
+			w.Write([]byte(fmt.Sprintf(`{"payload": [{"id": 1, "feature_flags": {
+				"usage_metrics_dash_enabled": %t,
+				"usage_metrics_link": "%s/superset/dashboard/usage-metrics/",
+				"usage_metrics_resource_id": "usage-metrics",
+				"usage_metrics_rls_rules": "team_id = 1"
+			}}]}`, enabled, mockServer.URL)))
+		case "/v1/teams/1/workspaces":
+			w.Write([]byte(`{"payload": [{"id": 2}, {"id": 3}, {"id": 4}]}`))
+		case "/api/v1/dashboard/usage-metrics/datasets":
+			w.Write([]byte(`{"result": [{"id": 17, "table_name": "usage_events"}]}`))
+		case "/api/v1/chart/data":
+			assert.Equal(t, "POST", r.Method)
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, query)
+			q := (*query)["queries"].([]interface{})[0].(map[string]interface{})
+			offset, limit := int(q["row_offset"].(float64)), int(q["row_limit"].(float64))
+
+			all := []interface{}{}
+			json.Unmarshal([]byte(rows), &all)
+			page := all[min(offset, len(all)):min(offset+limit, len(all))]
+			data, _ := json.Marshal(page)
+			w.Write([]byte(`{"result": [{"colnames": ["workspace_id", "user_id", "dashboard_views", "query_count"], "data": ` + string(data) + `}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return mockServer
+}
+
+func TestGetUsageDashboard_Disabled(t *testing.T) {
+	mockServer := mockUsageServer(t, false, `[]`, &map[string]interface{}{})
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	_, err := client.GetUsageDashboard(context.Background(), 1, nil)
+	assert.True(t, errors.Is(err, ErrUsageMetricsDisabled))
+
+	now := time.Now()
+	_, err = client.GetTeamUsage(context.Background(), 1, now.AddDate(0, -1, 0), now, nil)
+	assert.True(t, errors.Is(err, ErrUsageMetricsDisabled))
+}
+
+func TestGetWorkspaceUsage_SuccessfulResponse(t *testing.T) {
+	query := map[string]interface{}{}
+	mockServer := mockUsageServer(t, true, `[
+		{"workspace_id": 2, "user_id": 7, "dashboard_views": 1000, "query_count": 5000},
+		{"workspace_id": 2, "user_id": 8, "dashboard_views": 300, "query_count": 200}
+	]`, &query)
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	dashboard, err := client.GetUsageDashboard(context.Background(), 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, UsageDashboard{WorkspaceURL: mockServer.URL, ResourceID: "usage-metrics", RLSRules: "team_id = 1"}, *dashboard)
+
+	since := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	usage, err := client.GetWorkspaceUsage(context.Background(), 1, 2, since, until, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, usage.WorkspaceID)
+	assert.Equal(t, 2, usage.ActiveUsers)
+	assert.Equal(t, 1300, usage.DashboardViews)
+	assert.Equal(t, 5200, usage.QueryCount)
+	assert.Equal(t, "2023-07-01", usage.StartDate)
+
+	assert.Equal(t, map[string]interface{}{"id": float64(17), "type": "table"}, query["datasource"])
+	q := query["queries"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"col": "dttm", "op": "TEMPORAL_RANGE", "val": "2023-07-01 : 2023-08-01"},
+		map[string]interface{}{"col": "workspace_id", "op": "==", "val": float64(2)},
+	}, q["filters"])
+	assert.Nil(t, q["extras"])
+	assert.Equal(t, float64(usagePageSize), q["row_limit"])
+}
+
+func TestGetTeamUsage_PagesThroughRows(t *testing.T) {
+	pageSize := usagePageSize
+	usagePageSize = 2
+	defer func() { usagePageSize = pageSize }()
+
+	query := map[string]interface{}{}
+	mockServer := mockUsageServer(t, true, `[
+		{"workspace_id": 2, "user_id": 7, "dashboard_views": 1, "query_count": 1},
+		{"workspace_id": 2, "user_id": 8, "dashboard_views": 1, "query_count": 1},
+		{"workspace_id": 3, "user_id": 8, "dashboard_views": 1, "query_count": 1},
+		{"workspace_id": 3, "user_id": 9, "dashboard_views": 1, "query_count": 1},
+		{"workspace_id": 4, "user_id": 9, "dashboard_views": 1, "query_count": 1}
+	]`, &query)
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	now := time.Now()
+	usage, err := client.GetTeamUsage(context.Background(), 1, now.AddDate(0, -1, 0), now, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, UsageMetrics{ActiveUsers: 3, DashboardViews: 5, QueryCount: 5}, usage.Total())
+	assert.Equal(t, []int{9}, usage.Workspaces[2].ActiveUserIDs)
+
+	// The last page asked for started past the rows already read
+	q := query["queries"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(4), q["row_offset"])
+}
+
+func TestGetTeamUsage_Canceled(t *testing.T) {
+	mockServer := mockUsageServer(t, true, `[]`, &map[string]interface{}{})
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	now := time.Now()
+	_, err := client.GetTeamUsage(ctx, 1, now.AddDate(0, -1, 0), now, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGetWorkspaceUsage_InvalidWindow(t *testing.T) {
+	client := &PresetClient{
+		BaseURL:    "mockBaseURL",
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	since := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	_, err := client.GetWorkspaceUsage(context.Background(), 1, 2, since, until, nil)
+	assert.EqualError(t, err, "invalid usage window")
+}
+
+func TestGetTeamUsage_AggregatesWorkspaces(t *testing.T) {
+	mockServer := mockUsageServer(t, true, `[
+		{"workspace_id": 2, "user_id": 7, "dashboard_views": 4, "query_count": 60},
+		{"workspace_id": 2, "user_id": 8, "dashboard_views": 6, "query_count": 40},
+		{"workspace_id": 3, "user_id": 8, "dashboard_views": 5, "query_count": 20},
+		{"workspace_id": 3, "user_id": 9, "dashboard_views": 0, "query_count": 30}
+	]`, &map[string]interface{}{})
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	now := time.Now()
+	usage, err := client.GetTeamUsage(context.Background(), 1, now.AddDate(0, -1, 0), now, nil)
+	assert.NoError(t, err)
+	assert.Len(t, usage.Workspaces, 3)
+	assert.Equal(t, 3, usage.Workspaces[1].WorkspaceID)
+	assert.Equal(t, []int{8, 9}, usage.Workspaces[1].ActiveUserIDs)
+	assert.Equal(t, UsageMetrics{}, usage.Workspaces[2].UsageMetrics)
+	assert.Equal(t, UsageMetrics{ActiveUsers: 3, DashboardViews: 15, QueryCount: 150}, usage.Total())
+
+	// Without user IDs, active users can only be summed
+	usage.Workspaces[0].ActiveUserIDs = nil
+	assert.Equal(t, 4, usage.Total().ActiveUsers)
+}
+
+func TestGetTeamUsage_InternalServerError(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a 500 internal server error
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	now := time.Now()
+	_, err := client.GetTeamUsage(context.Background(), 1, now.AddDate(0, -1, 0), now, nil)
+	assert.Error(t, err)
+}
@@ -2,26 +2,44 @@ package preset
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
 )
 
+// WorkspaceStatus is the provisioning state of a workspace. Values the SDK doesn't know are kept as sent.
+type WorkspaceStatus string
+
+const (
+	WORKSPACE_PENDING  WorkspaceStatus = "pending"
+	WORKSPACE_READY    WorkspaceStatus = "ready"
+	WORKSPACE_DISABLED WorkspaceStatus = "disabled"
+	WORKSPACE_FAILED   WorkspaceStatus = "failed"
+	WORKSPACE_UNKNOWN  WorkspaceStatus = "unknown"
+)
+
+// Returns the status, or WORKSPACE_UNKNOWN if the SDK doesn't know it
+func (s WorkspaceStatus) OrUnknown() WorkspaceStatus {
+	switch s {
+	case WORKSPACE_PENDING, WORKSPACE_READY, WORKSPACE_DISABLED, WORKSPACE_FAILED:
+		return s
+	}
+	return WORKSPACE_UNKNOWN
+}
+
 // Returns all workspaces tied to a given Preset team
 func (c *PresetClient) GetAllWorkspaces(teamID int, authToken *string) (*[]Workspace, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/teams/%d/workspaces", c.BaseURL, teamID), nil)
-	if err != nil {
-		return nil, err
-	}
+	return c.getAllWorkspaces(context.Background(), teamID, authToken)
+}
 
-	body, err := c.doRequest(req, authToken)
+func (c *PresetClient) getAllWorkspaces(ctx context.Context, teamID int, authToken *string) (*[]Workspace, error) {
+	req, err := http.NewRequestWithContext(withOperation(ctx, "GetAllWorkspaces", teamID, 0), "GET", fmt.Sprintf("%s/v1/teams/%d/workspaces", c.BaseURL, teamID), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	tr := WorkspaceGetResponse{}
-	err = json.Unmarshal(body, &tr)
-
+	err = c.doDecode(req, authToken, &tr)
 	if err != nil {
 		return nil, err
 	}
@@ -32,18 +50,17 @@ func (c *PresetClient) GetAllWorkspaces(teamID int, authToken *string) (*[]Works
 
 // Returns all the members belonging to a given Preset workspace
 func (c *PresetClient) GetWorkspaceMembership(teamID int, workspaceID int, authToken *string) (*[]WorkspaceMembership, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/teams/%d/workspaces/%d/memberships", c.BaseURL, teamID, workspaceID), nil)
-	if err != nil {
-		return nil, err
-	}
+	return c.getWorkspaceMembership(context.Background(), teamID, workspaceID, authToken)
+}
 
-	body, err := c.doRequest(req, authToken)
+func (c *PresetClient) getWorkspaceMembership(ctx context.Context, teamID int, workspaceID int, authToken *string) (*[]WorkspaceMembership, error) {
+	req, err := http.NewRequestWithContext(withOperation(ctx, "GetWorkspaceMembership", teamID, workspaceID), "GET", fmt.Sprintf("%s/v1/teams/%d/workspaces/%d/memberships", c.BaseURL, teamID, workspaceID), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	wmgr := WorkspaceMembershipGetResponse{}
-	err = json.Unmarshal(body, &wmgr)
+	err = c.doDecode(req, authToken, &wmgr)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +71,10 @@ func (c *PresetClient) GetWorkspaceMembership(teamID int, workspaceID int, authT
 
 // Updates a given user's workspace role
 func (c *PresetClient) UpdateUserWorkspaceRole(teamID int, workspaceID int, userID int, roleIdentifier string, authToken *string) (*WorkspaceMembership, error) {
+	return c.updateUserWorkspaceRole(context.Background(), teamID, workspaceID, userID, roleIdentifier, authToken)
+}
+
+func (c *PresetClient) updateUserWorkspaceRole(ctx context.Context, teamID int, workspaceID int, userID int, roleIdentifier string, authToken *string) (*WorkspaceMembership, error) {
 	// Workspace role identifiers mapping
 	workspaceRoleIdentifiers := map[string]string{
 		"workspace admin": "Admin",
@@ -78,28 +99,60 @@ func (c *PresetClient) UpdateUserWorkspaceRole(teamID int, workspaceID int, user
 	}
 
 	// Convert the payload map to JSON
-	payloadBytes, err := json.Marshal(payload)
+	payloadBytes, err := c.codec().Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/v1/team/%d/workspaces/%d/membership", c.BaseURL, teamID, workspaceID), bytes.NewReader(payloadBytes))
+	req, err := http.NewRequestWithContext(withOperation(ctx, "UpdateUserWorkspaceRole", teamID, workspaceID), "PUT", fmt.Sprintf("%s/v1/team/%d/workspaces/%d/membership", c.BaseURL, teamID, workspaceID), bytes.NewReader(payloadBytes))
 	
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req, authToken)
+	wmur := WorkspaceMembershipUpdateResponse{}
+	err = c.doDecode(req, authToken, &wmur)
 	if err != nil {
 		return nil, err
 	}
 
-	wmur := WorkspaceMembershipUpdateResponse{}
-	err = json.Unmarshal(body, &wmur)
+	membership := wmur.Payload
+	return &membership, nil
+}
+
+// Creates a workspace in a given team. Workspaces start out pending while they are provisioned.
+func (c *PresetClient) CreateWorkspace(teamID int, payload WorkspacePayload, authToken *string) (*Workspace, error) {
+	if payload.Title == "" {
+		return nil, fmt.Errorf("missing workspace title")
+	}
+
+	payloadBytes, err := c.codec().Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	membership := wmur.Payload
-	return &membership, nil
-}
\ No newline at end of file
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "CreateWorkspace", teamID, 0), "POST", fmt.Sprintf("%s/v1/teams/%d/workspaces", c.BaseURL, teamID), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	wcr := WorkspaceCreateResponse{}
+	err = c.doDecode(req, authToken, &wcr)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := wcr.Payload
+	return &workspace, nil
+}
+
+// Deletes a workspace along with everything in it
+func (c *PresetClient) DeleteWorkspace(teamID int, workspaceID int, authToken *string) error {
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "DeleteWorkspace", teamID, workspaceID), "DELETE", fmt.Sprintf("%s/v1/teams/%d/workspaces/%d", c.BaseURL, teamID, workspaceID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
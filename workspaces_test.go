@@ -1,6 +1,7 @@
 package preset
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -244,3 +245,57 @@ func TestUpdateUserWorkspaceRole_InternalServerError(t *testing.T) {
 	_, err := client.UpdateUserWorkspaceRole(1, 2, 123, "primary contributor", nil)
 	assert.Error(t, err)
 }
+
+func TestWorkspaceStatus_OrUnknown(t *testing.T) {
+	assert.Equal(t, WORKSPACE_READY, WorkspaceStatus("ready").OrUnknown())
+	assert.Equal(t, WORKSPACE_UNKNOWN, WorkspaceStatus("hibernating").OrUnknown())
+}
+
+func TestCreateWorkspace_SuccessfulResponse(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/v1/teams/1/workspaces", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"title": "Analytics", "region": "us-east-1"}`, string(body))
+
+		w.Write([]byte(`{"payload": {"id": 9, "team_id": 1, "title": "Analytics", "workspace_status": "pending"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	workspace, err := client.CreateWorkspace(1, WorkspacePayload{Title: "Analytics", Region: "us-east-1"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, workspace.ID)
+	assert.Equal(t, WORKSPACE_PENDING, workspace.WorkspaceStatus)
+
+	_, err = client.CreateWorkspace(1, WorkspacePayload{}, nil)
+	assert.Error(t, err)
+}
+
+func TestDeleteWorkspace(t *testing.T) {
+	// Create a mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		if r.URL.Path != "/v1/teams/1/workspaces/9" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"payload": {}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &PresetClient{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Token:      "mockAccessToken",
+	}
+
+	assert.NoError(t, client.DeleteWorkspace(1, 9, nil))
+	assert.Error(t, client.DeleteWorkspace(1, 10, nil))
+}